@@ -0,0 +1,233 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package loggo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// OverflowPolicy determines what an AsyncWriter does with a new entry when
+// its queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the logging call until there is room in the queue.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest discards the entry being written.
+	OverflowDropNewest
+	// OverflowDropOldest discards the oldest queued entry to make room for
+	// the entry being written.
+	OverflowDropOldest
+	// OverflowDropBelowLevel discards the entry being written if its level
+	// is below the configured DropLevel, otherwise it blocks.
+	OverflowDropBelowLevel
+)
+
+// DefaultAsyncQueueSize is the queue size used by an AsyncWriter when none is
+// specified.
+const DefaultAsyncQueueSize = 1024
+
+// AsyncConfig holds the configuration for an AsyncWriter.
+type AsyncConfig struct {
+	// QueueSize is the maximum number of entries waiting to be written.
+	// If it is not positive, DefaultAsyncQueueSize is used.
+	QueueSize int
+	// Overflow is the policy applied when the queue is full.
+	Overflow OverflowPolicy
+	// DropLevel is the level below which entries are dropped when the
+	// queue is full and Overflow is OverflowDropBelowLevel.
+	DropLevel Level
+	// ErrorHandler, if set, is called with any error returned by the
+	// wrapped writer. Errors are otherwise discarded, as there is no
+	// logging call left to return them to.
+	ErrorHandler func(error)
+}
+
+// AsyncWriter is a Writer that queues entries and writes them to the wrapped
+// writer from a separate goroutine, so that a slow writer doesn't stall the
+// goroutines that are logging.
+type AsyncWriter struct {
+	writer Writer
+	config AsyncConfig
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  []asyncEntry
+	closed bool
+	// writerClosed records that the wrapped writer has been closed, so
+	// that it is only closed once.
+	writerClosed bool
+	// enqueued counts the entries accepted onto the queue, and completed
+	// counts the entries that have since been written or evicted. Flush
+	// uses them to know when everything queued before it has been handled.
+	enqueued  uint64
+	completed uint64
+	dropped   uint64
+
+	done chan struct{}
+}
+
+type asyncEntry struct {
+	ctx   context.Context
+	entry Entry
+}
+
+// NewAsyncWriter returns an AsyncWriter that writes to the given writer
+// from a background goroutine. Close must be called to stop the goroutine.
+func NewAsyncWriter(writer Writer, config AsyncConfig) *AsyncWriter {
+	if config.QueueSize <= 0 {
+		config.QueueSize = DefaultAsyncQueueSize
+	}
+	w := &AsyncWriter{
+		writer: writer,
+		config: config,
+		queue:  make([]asyncEntry, 0, config.QueueSize),
+		done:   make(chan struct{}),
+	}
+	w.cond = sync.NewCond(&w.mu)
+	go w.loop()
+	return w
+}
+
+// Write queues the entry to be written by the wrapped writer. If the queue is
+// full, the configured OverflowPolicy decides whether the entry is dropped or
+// the call blocks. An error is returned if the writer has been closed.
+func (w *AsyncWriter) Write(ctx context.Context, entry Entry) error {
+	// The entry is written after the logging call has returned, so the
+	// cancellation of the caller's context must not affect it.
+	ctx = context.WithoutCancel(ctx)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for {
+		if w.closed {
+			return fmt.Errorf("async writer is closed")
+		}
+		if len(w.queue) < w.config.QueueSize {
+			w.queue = append(w.queue, asyncEntry{ctx: ctx, entry: entry})
+			w.enqueued++
+			w.cond.Broadcast()
+			return nil
+		}
+
+		switch w.config.Overflow {
+		case OverflowDropNewest:
+			w.dropped++
+			return nil
+		case OverflowDropOldest:
+			w.queue[0] = asyncEntry{}
+			w.queue = w.queue[1:]
+			w.dropped++
+			w.completed++
+			continue
+		case OverflowDropBelowLevel:
+			if entry.Level < w.config.DropLevel {
+				w.dropped++
+				return nil
+			}
+		}
+		w.cond.Wait()
+	}
+}
+
+// Concurrent implements ConcurrentWriter. Entries are queued under a lock,
+// and the wrapped writer is only called from the background goroutine.
+func (w *AsyncWriter) Concurrent() {}
+
+// Dropped returns the number of entries that have been discarded because the
+// queue was full.
+func (w *AsyncWriter) Dropped() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.dropped
+}
+
+// Flush blocks until every entry queued before the call has been written, or
+// the context is done. The wrapped writer is then flushed.
+func (w *AsyncWriter) Flush(ctx context.Context) error {
+	if err := w.drain(ctx); err != nil {
+		return err
+	}
+	return FlushWriter(ctx, w.writer)
+}
+
+func (w *AsyncWriter) drain(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	stop := context.AfterFunc(ctx, w.wake)
+	defer stop()
+
+	target := w.enqueued
+	for w.completed < target {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		w.cond.Wait()
+	}
+	return nil
+}
+
+// Close stops accepting new entries and blocks until the queued entries have
+// been written, or the context is done. The wrapped writer is then closed.
+// Closing an already closed writer waits for the queue to drain again, but
+// the wrapped writer is only closed once.
+func (w *AsyncWriter) Close(ctx context.Context) error {
+	w.mu.Lock()
+	w.closed = true
+	w.cond.Broadcast()
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	w.mu.Lock()
+	writerClosed := w.writerClosed
+	w.writerClosed = true
+	w.mu.Unlock()
+	if writerClosed {
+		return nil
+	}
+	return CloseWriter(ctx, w.writer)
+}
+
+// wake wakes all the goroutines waiting on the writer.
+func (w *AsyncWriter) wake() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.cond.Broadcast()
+}
+
+func (w *AsyncWriter) loop() {
+	defer close(w.done)
+	for {
+		w.mu.Lock()
+		for len(w.queue) == 0 && !w.closed {
+			w.cond.Wait()
+		}
+		if len(w.queue) == 0 {
+			w.mu.Unlock()
+			return
+		}
+		item := w.queue[0]
+		w.queue[0] = asyncEntry{}
+		w.queue = w.queue[1:]
+		w.cond.Broadcast()
+		w.mu.Unlock()
+
+		if err := w.writer.Write(item.ctx, item.entry); err != nil && w.config.ErrorHandler != nil {
+			w.config.ErrorHandler(err)
+		}
+
+		w.mu.Lock()
+		w.completed++
+		w.cond.Broadcast()
+		w.mu.Unlock()
+	}
+}
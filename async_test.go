@@ -0,0 +1,254 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package loggo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juju/loggo/v3"
+	"github.com/juju/tc"
+)
+
+type AsyncWriterSuite struct{}
+
+func TestAsyncWriterSuite(t *testing.T) {
+	tc.Run(t, &AsyncWriterSuite{})
+}
+
+// gateWriter blocks every write until it is released, so that the queue of
+// an AsyncWriter can be filled deterministically.
+type gateWriter struct {
+	loggo.TestWriter
+	started chan struct{}
+	release chan struct{}
+}
+
+func newGateWriter() *gateWriter {
+	return &gateWriter{
+		started: make(chan struct{}, 100),
+		release: make(chan struct{}),
+	}
+}
+
+func (w *gateWriter) Write(ctx context.Context, entry loggo.Entry) error {
+	w.started <- struct{}{}
+	<-w.release
+	return w.TestWriter.Write(ctx, entry)
+}
+
+// fill writes the first entry and waits until the background goroutine is
+// blocked on it, then writes the given entries into the queue.
+func (w *gateWriter) fill(c *tc.C, async *loggo.AsyncWriter, entries ...loggo.Entry) {
+	err := async.Write(c.Context(), loggo.Entry{Level: loggo.INFO, Message: "in flight"})
+	c.Assert(err, tc.IsNil)
+	select {
+	case <-w.started:
+	case <-time.After(5 * time.Second):
+		c.Fatalf("timed out waiting for write to start")
+	}
+	for _, entry := range entries {
+		err := async.Write(c.Context(), entry)
+		c.Assert(err, tc.IsNil)
+	}
+}
+
+func messages(entries []loggo.Entry) []string {
+	var result []string
+	for _, entry := range entries {
+		result = append(result, entry.Message)
+	}
+	return result
+}
+
+func (*AsyncWriterSuite) TestWriteAndFlush(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	async := loggo.NewAsyncWriter(writer, loggo.AsyncConfig{})
+	defer func() { _ = async.Close(context.Background()) }()
+
+	for i := 0; i < 100; i++ {
+		err := async.Write(c.Context(), loggo.Entry{Level: loggo.INFO, Message: "message"})
+		c.Assert(err, tc.IsNil)
+	}
+	err := async.Flush(c.Context())
+	c.Assert(err, tc.IsNil)
+	c.Check(writer.Log(), tc.HasLen, 100)
+	c.Check(async.Dropped(), tc.Equals, uint64(0))
+}
+
+func (*AsyncWriterSuite) TestCloseDrainsQueue(c *tc.C) {
+	writer := newGateWriter()
+	async := loggo.NewAsyncWriter(writer, loggo.AsyncConfig{QueueSize: 2})
+	writer.fill(c, async,
+		loggo.Entry{Level: loggo.INFO, Message: "one"},
+		loggo.Entry{Level: loggo.INFO, Message: "two"},
+	)
+	close(writer.release)
+
+	err := async.Close(c.Context())
+	c.Assert(err, tc.IsNil)
+	c.Check(messages(writer.Log()), tc.DeepEquals, []string{"in flight", "one", "two"})
+
+	err = async.Write(c.Context(), loggo.Entry{Level: loggo.INFO, Message: "late"})
+	c.Check(err, tc.ErrorMatches, "async writer is closed")
+}
+
+func (*AsyncWriterSuite) TestCloseTimeout(c *tc.C) {
+	writer := newGateWriter()
+	async := loggo.NewAsyncWriter(writer, loggo.AsyncConfig{QueueSize: 2})
+	writer.fill(c, async)
+	defer close(writer.release)
+
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Millisecond)
+	defer cancel()
+	err := async.Close(ctx)
+	c.Assert(err, tc.ErrorIs, context.DeadlineExceeded)
+}
+
+func (*AsyncWriterSuite) TestFlushTimeout(c *tc.C) {
+	writer := newGateWriter()
+	async := loggo.NewAsyncWriter(writer, loggo.AsyncConfig{QueueSize: 2})
+	writer.fill(c, async)
+	defer func() {
+		close(writer.release)
+		_ = async.Close(context.Background())
+	}()
+
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Millisecond)
+	defer cancel()
+	err := async.Flush(ctx)
+	c.Assert(err, tc.ErrorIs, context.DeadlineExceeded)
+}
+
+func (*AsyncWriterSuite) TestOverflowDropNewest(c *tc.C) {
+	writer := newGateWriter()
+	async := loggo.NewAsyncWriter(writer, loggo.AsyncConfig{
+		QueueSize: 2,
+		Overflow:  loggo.OverflowDropNewest,
+	})
+	writer.fill(c, async,
+		loggo.Entry{Level: loggo.INFO, Message: "one"},
+		loggo.Entry{Level: loggo.INFO, Message: "two"},
+		loggo.Entry{Level: loggo.INFO, Message: "three"},
+		loggo.Entry{Level: loggo.INFO, Message: "four"},
+	)
+	c.Check(async.Dropped(), tc.Equals, uint64(2))
+	close(writer.release)
+
+	err := async.Close(c.Context())
+	c.Assert(err, tc.IsNil)
+	c.Check(messages(writer.Log()), tc.DeepEquals, []string{"in flight", "one", "two"})
+}
+
+func (*AsyncWriterSuite) TestOverflowDropOldest(c *tc.C) {
+	writer := newGateWriter()
+	async := loggo.NewAsyncWriter(writer, loggo.AsyncConfig{
+		QueueSize: 2,
+		Overflow:  loggo.OverflowDropOldest,
+	})
+	writer.fill(c, async,
+		loggo.Entry{Level: loggo.INFO, Message: "one"},
+		loggo.Entry{Level: loggo.INFO, Message: "two"},
+		loggo.Entry{Level: loggo.INFO, Message: "three"},
+		loggo.Entry{Level: loggo.INFO, Message: "four"},
+	)
+	c.Check(async.Dropped(), tc.Equals, uint64(2))
+
+	// Flush must not wait on the entries that were evicted.
+	close(writer.release)
+	err := async.Flush(c.Context())
+	c.Assert(err, tc.IsNil)
+	c.Check(messages(writer.Log()), tc.DeepEquals, []string{"in flight", "three", "four"})
+
+	err = async.Close(c.Context())
+	c.Assert(err, tc.IsNil)
+}
+
+func (*AsyncWriterSuite) TestOverflowDropBelowLevel(c *tc.C) {
+	writer := newGateWriter()
+	async := loggo.NewAsyncWriter(writer, loggo.AsyncConfig{
+		QueueSize: 1,
+		Overflow:  loggo.OverflowDropBelowLevel,
+		DropLevel: loggo.WARNING,
+	})
+	writer.fill(c, async,
+		loggo.Entry{Level: loggo.INFO, Message: "one"},
+		loggo.Entry{Level: loggo.DEBUG, Message: "dropped"},
+	)
+	c.Check(async.Dropped(), tc.Equals, uint64(1))
+
+	// A warning blocks until there is room rather than being dropped.
+	written := make(chan error)
+	go func() {
+		written <- async.Write(context.Background(), loggo.Entry{Level: loggo.WARNING, Message: "kept"})
+	}()
+	select {
+	case <-written:
+		c.Fatalf("write should block while the queue is full")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(writer.release)
+	c.Assert(<-written, tc.IsNil)
+
+	err := async.Close(c.Context())
+	c.Assert(err, tc.IsNil)
+	c.Check(messages(writer.Log()), tc.DeepEquals, []string{"in flight", "one", "kept"})
+	c.Check(async.Dropped(), tc.Equals, uint64(1))
+}
+
+func (*AsyncWriterSuite) TestErrorHandler(c *tc.C) {
+	var errs []error
+	failing := writerFunc(func(context.Context, loggo.Entry) error {
+		return errors.New("boom")
+	})
+	async := loggo.NewAsyncWriter(failing, loggo.AsyncConfig{
+		ErrorHandler: func(err error) { errs = append(errs, err) },
+	})
+	err := async.Write(c.Context(), loggo.Entry{Level: loggo.INFO, Message: "message"})
+	c.Assert(err, tc.IsNil)
+
+	err = async.Close(c.Context())
+	c.Assert(err, tc.IsNil)
+	c.Assert(errs, tc.HasLen, 1)
+	c.Check(errs[0], tc.ErrorMatches, "boom")
+}
+
+func (*AsyncWriterSuite) TestContextNotCancelled(c *tc.C) {
+	var received context.Context
+	w := writerFunc(func(ctx context.Context, _ loggo.Entry) error {
+		received = ctx
+		return nil
+	})
+	async := loggo.NewAsyncWriter(w, loggo.AsyncConfig{})
+
+	ctx, cancel := context.WithCancel(context.WithValue(c.Context(), requestIDKey{}, "req-1"))
+	err := async.Write(ctx, loggo.Entry{Level: loggo.INFO, Message: "message"})
+	c.Assert(err, tc.IsNil)
+	cancel()
+
+	err = async.Close(c.Context())
+	c.Assert(err, tc.IsNil)
+	c.Check(received.Err(), tc.IsNil)
+	c.Check(received.Value(requestIDKey{}), tc.Equals, "req-1")
+}
+
+func (*AsyncWriterSuite) TestFlushAndCloseWrappedWriter(c *tc.C) {
+	var calls []string
+	wrapped := &lifecycleWriter{writer: writer{name: "wrapped"}, calls: &calls}
+	async := loggo.NewAsyncWriter(wrapped, loggo.AsyncConfig{})
+
+	err := async.Write(c.Context(), loggo.Entry{Level: loggo.INFO, Message: "message"})
+	c.Assert(err, tc.IsNil)
+	err = async.Flush(c.Context())
+	c.Assert(err, tc.IsNil)
+	c.Check(wrapped.Log(), tc.HasLen, 1)
+
+	err = async.Close(c.Context())
+	c.Assert(err, tc.IsNil)
+	err = async.Close(c.Context())
+	c.Assert(err, tc.IsNil)
+	c.Check(calls, tc.DeepEquals, []string{"flush wrapped", "close wrapped"})
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package attrs
+
+import "time"
+
+// BadKey is the key given to a value in a list of key/value pairs that isn't
+// preceded by a string key.
+const BadKey = "!BADKEY"
+
+// Args converts a list of typed attributes and alternating key/value pairs
+// into typed attributes, keeping them in the same order as provided. The
+// value of each pair is converted with Of. A value that is neither a typed
+// attribute nor preceded by a string key, such as a key at the end of the
+// list without a value, is added with the key BadKey.
+func Args(args ...any) []any {
+	if len(args) == 0 {
+		return nil
+	}
+	result := make([]any, 0, len(args))
+	for len(args) > 0 {
+		switch key := args[0].(type) {
+		case string:
+			if len(args) == 1 {
+				result = append(result, String(BadKey, key))
+				args = args[1:]
+				continue
+			}
+			result = append(result, Of(key, args[1]))
+			args = args[2:]
+		default:
+			if isAttr(key) {
+				result = append(result, key)
+			} else {
+				result = append(result, Of(BadKey, key))
+			}
+			args = args[1:]
+		}
+	}
+	return result
+}
+
+// Of creates a typed attribute with the given key, choosing the type from the
+// value. Integers are widened to int64 or uint64 and float32 to float64,
+// errors are stored with Error, and values of any other type are stored with
+// Any.
+func Of(k string, v any) any {
+	switch v := v.(type) {
+	case string:
+		return String(k, v)
+	case int:
+		return Int(k, v)
+	case int8:
+		return Int64(k, int64(v))
+	case int16:
+		return Int64(k, int64(v))
+	case int32:
+		return Int64(k, int64(v))
+	case int64:
+		return Int64(k, v)
+	case uint:
+		return Uint64(k, uint64(v))
+	case uint8:
+		return Uint64(k, uint64(v))
+	case uint16:
+		return Uint64(k, uint64(v))
+	case uint32:
+		return Uint64(k, uint64(v))
+	case uint64:
+		return Uint64(k, v)
+	case float32:
+		return Float64(k, float64(v))
+	case float64:
+		return Float64(k, v)
+	case bool:
+		return Bool(k, v)
+	case time.Time:
+		return Time(k, v)
+	case time.Duration:
+		return Duration(k, v)
+	case []string:
+		return Strings(k, v)
+	case []int:
+		return Ints(k, v)
+	case map[string]string:
+		return StringMap(k, v)
+	case []byte:
+		return Bytes(k, v)
+	case error:
+		return Error(k, v)
+	default:
+		return Any(k, v)
+	}
+}
@@ -1,7 +1,7 @@
 // Package attrs provides typed key-value attributes for structured logging.
 // Attributes are used to attach additional metadata to log entries in a
 // type-safe manner, supporting common Go types such as string, int, float64,
-// bool, time.Time, and time.Duration.
+// bool, time.Time, time.Duration, slices, maps and errors.
 package attrs
 
 import (
@@ -79,54 +79,79 @@ func Any(k string, v any) AttrValue[any] {
 	return attr[any]{key: k, value: v}
 }
 
+// Strings creates a []string-typed attribute with the given key and value.
+func Strings(k string, v []string) AttrValue[[]string] {
+	return attr[[]string]{key: k, value: v}
+}
+
+// Ints creates a []int-typed attribute with the given key and value.
+func Ints(k string, v []int) AttrValue[[]int] {
+	return attr[[]int]{key: k, value: v}
+}
+
+// StringMap creates a map[string]string-typed attribute with the given key
+// and value.
+func StringMap(k string, v map[string]string) AttrValue[map[string]string] {
+	return attr[map[string]string]{key: k, value: v}
+}
+
+// Bytes creates a []byte-typed attribute with the given key and value. Each
+// writer chooses how to encode the bytes, such as hex or base64.
+func Bytes(k string, v []byte) AttrValue[[]byte] {
+	return attr[[]byte]{key: k, value: v}
+}
+
+// Stringer creates a fmt.Stringer-typed attribute with the given key and
+// value. The value is rendered by calling its String method.
+func Stringer(k string, v fmt.Stringer) AttrValue[fmt.Stringer] {
+	return attr[fmt.Stringer]{key: k, value: v}
+}
+
+// Group creates an attribute that nests the given attributes under the given
+// name, for example http.method and http.status. The attributes may be typed
+// attributes or alternating key/value pairs, as accepted by Args. Groups with
+// an empty name are inlined by the writers.
+func Group(name string, attrs ...any) AttrValue[[]any] {
+	return attr[[]any]{key: name, value: Args(attrs...)}
+}
+
 // Valid checks that all attributes are of a valid type.
 func Valid(attrs []any) error {
 	for _, attr := range attrs {
-		switch a := attr.(type) {
-		case AttrValue[string]:
-		case AttrValue[int]:
-		case AttrValue[int64]:
-		case AttrValue[uint64]:
-		case AttrValue[float64]:
-		case AttrValue[bool]:
-		case AttrValue[time.Time]:
-		case AttrValue[time.Duration]:
-		case AttrValue[any]:
-		default:
-			return fmt.Errorf("invalid attribute type %T", a)
+		if !isAttr(attr) {
+			return fmt.Errorf("invalid attribute type %T", attr)
 		}
 	}
 	return nil
 }
 
 // Attrs separates attributes into generic and typed attributes, but keeps
-// them in the same order as provided.
+// them in the same order as provided. If none of the attributes are typed,
+// the provided slice is returned as the generic attributes without being
+// copied.
 func Attrs(attrs ...any) ([]any, []any) {
+	typed := false
+	for _, attr := range attrs {
+		if isAttr(attr) {
+			typed = true
+			break
+		}
+	}
+	if !typed {
+		if len(attrs) == 0 {
+			return nil, nil
+		}
+		return attrs, nil
+	}
+
 	var (
 		a []any
 		b []any
 	)
 	for _, attr := range attrs {
-		switch attr.(type) {
-		case AttrValue[string]:
-			b = append(b, attr)
-		case AttrValue[int]:
-			b = append(b, attr)
-		case AttrValue[int64]:
-			b = append(b, attr)
-		case AttrValue[uint64]:
-			b = append(b, attr)
-		case AttrValue[float64]:
-			b = append(b, attr)
-		case AttrValue[bool]:
-			b = append(b, attr)
-		case AttrValue[time.Time]:
-			b = append(b, attr)
-		case AttrValue[time.Duration]:
-			b = append(b, attr)
-		case AttrValue[any]:
+		if isAttr(attr) {
 			b = append(b, attr)
-		default:
+		} else {
 			a = append(a, attr)
 		}
 	}
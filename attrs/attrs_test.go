@@ -4,6 +4,12 @@
 package attrs
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
 	"testing"
 	"time"
 )
@@ -229,3 +235,279 @@ func TestAttrsEmpty(t *testing.T) {
 		t.Errorf("expected nil typed, got %v", typed)
 	}
 }
+
+func TestSecretAttr(t *testing.T) {
+	a := Secret("password", "hunter2")
+	if a.Key() != "password" {
+		t.Errorf("expected key %q, got %q", "password", a.Key())
+	}
+	secret, ok := a.Value().(SecretValue)
+	if !ok {
+		t.Fatalf("expected SecretValue, got %T", a.Value())
+	}
+	if secret.Reveal() != "hunter2" {
+		t.Errorf("expected revealed value %q, got %v", "hunter2", secret.Reveal())
+	}
+}
+
+func TestSecretRendering(t *testing.T) {
+	secret := Secret("password", "hunter2").Value()
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%x", "%d"} {
+		if got := fmt.Sprintf(format, secret); got != Redacted {
+			t.Errorf("expected %s to render %q, got %q", format, Redacted, got)
+		}
+	}
+	if got := fmt.Sprintf("%q", secret); got != `"***"` {
+		t.Errorf("expected %%q to render %q, got %q", `"***"`, got)
+	}
+	data, err := json.Marshal(map[string]any{"password": secret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"password":"***"}` {
+		t.Errorf("expected redacted JSON, got %s", data)
+	}
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("msg", "password", secret)
+	if !strings.Contains(buf.String(), "password=***") || strings.Contains(buf.String(), "hunter2") {
+		t.Errorf("expected redacted slog output, got %q", buf.String())
+	}
+}
+
+func TestLazyAttr(t *testing.T) {
+	calls := 0
+	a := Lazy("dump", func() []int {
+		calls++
+		return []int{1, 2, 3}
+	})
+	if a.Key() != "dump" {
+		t.Errorf("expected key %q, got %q", "dump", a.Key())
+	}
+	if calls != 0 {
+		t.Fatalf("expected value not to be computed, got %d calls", calls)
+	}
+	for i := 0; i < 2; i++ {
+		if got := fmt.Sprint(a.Value()); got != "[1 2 3]" {
+			t.Errorf("expected value %q, got %q", "[1 2 3]", got)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected value to be computed once, got %d calls", calls)
+	}
+	if err := Valid([]any{a}); err != nil {
+		t.Errorf("expected lazy attr to be valid, got %v", err)
+	}
+}
+
+func TestIsLazy(t *testing.T) {
+	calls := 0
+	a := Lazy("dump", func() int {
+		calls++
+		return 1
+	})
+	if !IsLazy(a) {
+		t.Errorf("expected lazy attr to be lazy")
+	}
+	if IsLazy(Int("count", 1)) || IsLazy(Any("value", 1)) {
+		t.Errorf("expected other attrs not to be lazy")
+	}
+	if calls != 0 {
+		t.Errorf("expected value not to be computed, got %d calls", calls)
+	}
+}
+
+type expensive struct{ calls *int }
+
+func (e expensive) LogValue() slog.Value {
+	*e.calls++
+	return slog.StringValue("computed")
+}
+
+func TestResolve(t *testing.T) {
+	calls := 0
+	if got := Resolve(expensive{calls: &calls}); got != "computed" {
+		t.Errorf("expected %q, got %v", "computed", got)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+	if got := Resolve(42); got != 42 {
+		t.Errorf("expected %d, got %v", 42, got)
+	}
+	if got := Resolve(Secret("password", "hunter2").Value()); got != Redacted {
+		t.Errorf("expected %q, got %v", Redacted, got)
+	}
+}
+
+func TestArgs(t *testing.T) {
+	now := time.Now()
+	got := Args(
+		"name", "bob",
+		Int("typed", 1),
+		"count", int32(3),
+		"ok", true,
+		"when", now,
+		42,
+		"dangling",
+	)
+	expected := []any{
+		String("name", "bob"),
+		Int("typed", 1),
+		Int64("count", 3),
+		Bool("ok", true),
+		Time("when", now),
+		Int(BadKey, 42),
+		String(BadKey, "dangling"),
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d attrs, got %d: %v", len(expected), len(got), got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("attr %d: expected %#v, got %#v", i, expected[i], got[i])
+		}
+	}
+	if Args() != nil {
+		t.Errorf("expected nil for no args")
+	}
+}
+
+func TestOf(t *testing.T) {
+	type custom struct{ X int }
+	tests := []struct {
+		value    any
+		expected any
+	}{
+		{"s", String("k", "s")},
+		{1, Int("k", 1)},
+		{int64(2), Int64("k", 2)},
+		{uint8(3), Uint64("k", 3)},
+		{float32(1.5), Float64("k", 1.5)},
+		{time.Second, Duration("k", time.Second)},
+		{custom{X: 1}, Any("k", custom{X: 1})},
+	}
+	for _, test := range tests {
+		if got := Of("k", test.value); got != test.expected {
+			t.Errorf("Of(%#v): expected %#v, got %#v", test.value, test.expected, got)
+		}
+	}
+}
+
+func TestGroupAttr(t *testing.T) {
+	a := Group("http", String("method", "GET"), "status", 200)
+	if a.Key() != "http" {
+		t.Errorf("expected key %q, got %q", "http", a.Key())
+	}
+	members := a.Value()
+	if len(members) != 2 || members[0] != String("method", "GET") || members[1] != Int("status", 200) {
+		t.Errorf("unexpected members %#v", members)
+	}
+	if err := Valid([]any{a}); err != nil {
+		t.Errorf("expected group to be valid, got %v", err)
+	}
+	if _, typed := Attrs("arg", a); len(typed) != 1 {
+		t.Errorf("expected group to be a typed attr")
+	}
+}
+
+type locatedError struct {
+	msg string
+}
+
+func (e *locatedError) Error() string           { return e.msg }
+func (e *locatedError) Location() (string, int) { return "file.go", 12 }
+func (e *locatedError) StackTrace() []string    { return []string{"main.main"} }
+
+func TestErrAttr(t *testing.T) {
+	err := errors.New("boom")
+	a := Err(err)
+	if a.Key() != ErrorKey {
+		t.Errorf("expected key %q, got %q", ErrorKey, a.Key())
+	}
+	if a.Value() != err {
+		t.Errorf("expected value %v, got %v", err, a.Value())
+	}
+	if err := Valid([]any{a}); err != nil {
+		t.Errorf("expected error attr to be valid, got %v", err)
+	}
+	if got := Of("cause", err); got != Error("cause", err) {
+		t.Errorf("expected Of to create an error attr, got %#v", got)
+	}
+}
+
+func TestErrorDetails(t *testing.T) {
+	located := &locatedError{msg: "located"}
+	err := fmt.Errorf("outer: %w", errors.Join(located, errors.New("second")))
+
+	detail := Details(err)
+	if detail.Message != err.Error() || detail.Type != "*fmt.wrapError" {
+		t.Errorf("unexpected detail %#v", detail)
+	}
+	if len(detail.Causes) != 1 || len(detail.Causes[0].Causes) != 2 {
+		t.Fatalf("expected a joined cause with two errors, got %#v", detail.Causes)
+	}
+	first := detail.Causes[0].Causes[0]
+	if first.Message != "located" || first.Location != "file.go" || first.Line != 12 {
+		t.Errorf("unexpected located detail %#v", first)
+	}
+	if len(first.Stack) != 1 || first.Stack[0] != "main.main" {
+		t.Errorf("unexpected stack %v", first.Stack)
+	}
+	if second := detail.Causes[0].Causes[1]; second.Message != "second" {
+		t.Errorf("unexpected second cause %#v", second)
+	}
+
+	data, jsonErr := json.Marshal(Details(fmt.Errorf("wrapped: %w", errors.New("cause"))))
+	if jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+	expected := `{"message":"wrapped: cause","type":"*fmt.wrapError","causes":[{"message":"cause","type":"*errors.errorString"}]}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+}
+
+func TestErrorDetailsDepth(t *testing.T) {
+	err := errors.New("root")
+	for i := 0; i < MaxErrorDepth*2; i++ {
+		err = fmt.Errorf("wrap: %w", err)
+	}
+	depth := 0
+	for detail := Details(err); ; detail = detail.Causes[0] {
+		depth++
+		if len(detail.Causes) == 0 {
+			break
+		}
+	}
+	if depth != MaxErrorDepth {
+		t.Errorf("expected depth %d, got %d", MaxErrorDepth, depth)
+	}
+	if detail := Details(nil); detail.Message != "" {
+		t.Errorf("expected empty detail for nil error, got %#v", detail)
+	}
+}
+
+func TestCollectionAttrs(t *testing.T) {
+	collections := []any{
+		Strings("names", []string{"a", "b"}),
+		Ints("counts", []int{1, 2}),
+		StringMap("tags", map[string]string{"k": "v"}),
+		Bytes("payload", []byte{0xde, 0xad}),
+		Stringer("duration", time.Second),
+	}
+	if err := Valid(collections); err != nil {
+		t.Errorf("expected collection attrs to be valid, got %v", err)
+	}
+	if got := Strings("names", []string{"a"}).Value(); len(got) != 1 || got[0] != "a" {
+		t.Errorf("unexpected strings value %v", got)
+	}
+	if got := Stringer("duration", time.Second).Value().String(); got != "1s" {
+		t.Errorf("unexpected stringer value %q", got)
+	}
+	if _, ok := Of("payload", []byte("x")).(AttrValue[[]byte]); !ok {
+		t.Errorf("expected Of to create a bytes attr")
+	}
+	if _, ok := Of("tags", map[string]string{}).(AttrValue[map[string]string]); !ok {
+		t.Errorf("expected Of to create a string map attr")
+	}
+}
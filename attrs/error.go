@@ -0,0 +1,99 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package attrs
+
+import "fmt"
+
+// ErrorKey is the key used by Err.
+const ErrorKey = "error"
+
+// MaxErrorDepth is the maximum depth to which Details walks the chain of an
+// error, guarding against chains that are very long or cyclic.
+const MaxErrorDepth = 32
+
+// ErrorLocator is implemented by errors that record where they were created,
+// as a file or function, and line.
+type ErrorLocator interface {
+	Location() (string, int)
+}
+
+// ErrorStackTracer is implemented by errors that record the stack trace of
+// where they were created.
+type ErrorStackTracer interface {
+	StackTrace() []string
+}
+
+// Err creates an error-typed attribute with the key ErrorKey.
+func Err(err error) AttrValue[error] {
+	return Error(ErrorKey, err)
+}
+
+// Error creates an error-typed attribute with the given key and error. Writers
+// render the error with its chain of causes, as returned by Details.
+func Error(k string, err error) AttrValue[error] {
+	return attr[error]{key: k, value: err}
+}
+
+// ErrorDetail is a structured description of an error and its causes.
+type ErrorDetail struct {
+	// Message is the text of the error.
+	Message string `json:"message"`
+	// Type is the Go type of the error.
+	Type string `json:"type"`
+	// Location and Line are where the error was created, if the error
+	// implements ErrorLocator.
+	Location string `json:"location,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	// Stack is the stack trace of the error, if it implements
+	// ErrorStackTracer.
+	Stack []string `json:"stack,omitempty"`
+	// Causes are the errors wrapped by the error, found with an Unwrap
+	// method returning either an error or a slice of errors, as used by
+	// errors.Join.
+	Causes []ErrorDetail `json:"causes,omitempty"`
+}
+
+// String returns the message of the error.
+func (d ErrorDetail) String() string {
+	return d.Message
+}
+
+// Details returns the description of the error and its chain of causes, to a
+// depth of MaxErrorDepth. A nil error has an empty description.
+func Details(err error) ErrorDetail {
+	if err == nil {
+		return ErrorDetail{}
+	}
+	return details(err, 1)
+}
+
+func details(err error, depth int) ErrorDetail {
+	detail := ErrorDetail{
+		Message: err.Error(),
+		Type:    fmt.Sprintf("%T", err),
+	}
+	if locator, ok := err.(ErrorLocator); ok {
+		detail.Location, detail.Line = locator.Location()
+	}
+	if tracer, ok := err.(ErrorStackTracer); ok {
+		detail.Stack = tracer.StackTrace()
+	}
+	if depth >= MaxErrorDepth {
+		return detail
+	}
+
+	var causes []error
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		causes = []error{e.Unwrap()}
+	case interface{ Unwrap() []error }:
+		causes = e.Unwrap()
+	}
+	for _, cause := range causes {
+		if cause != nil {
+			detail.Causes = append(detail.Causes, details(cause, depth+1))
+		}
+	}
+	return detail
+}
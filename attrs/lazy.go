@@ -0,0 +1,62 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package attrs
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// LogValuer is implemented by values that compute the value to be logged when
+// an entry is written, rather than when it is created. It is the same
+// interface as slog.LogValuer, so that values written for slog can be used
+// unchanged.
+type LogValuer = slog.LogValuer
+
+// Resolve returns the value to be logged for v. If v is a LogValuer, it is
+// resolved, repeatedly if need be, otherwise v is returned as it is.
+func Resolve(v any) any {
+	if _, ok := v.(LogValuer); !ok {
+		return v
+	}
+	return slog.AnyValue(v).Resolve().Any()
+}
+
+// Lazy creates an attribute whose value is computed by calling fn the first
+// time Value is called. Writers only call Value for entries that are written,
+// so fn is not called for entries that are filtered out by level. The result
+// is cached, so fn is called at most once, although it may be called from a
+// different goroutine to the one that created the attribute.
+func Lazy[T any](k string, fn func() T) AttrValue[any] {
+	return &lazyAttr[T]{key: k, fn: fn}
+}
+
+// IsLazy reports whether attr was created by Lazy, without evaluating it.
+func IsLazy(attr any) bool {
+	_, ok := attr.(interface{ lazy() })
+	return ok
+}
+
+type lazyAttr[T any] struct {
+	key   string
+	once  sync.Once
+	fn    func() T
+	value T
+}
+
+func (a *lazyAttr[T]) lazy() {}
+
+// Key returns the attribute's key name.
+func (a *lazyAttr[T]) Key() string {
+	return a.key
+}
+
+// Value returns the attribute's value, computing it on the first call.
+func (a *lazyAttr[T]) Value() any {
+	a.once.Do(func() {
+		a.value = a.fn()
+		a.fn = nil
+	})
+	return a.value
+}
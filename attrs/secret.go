@@ -0,0 +1,67 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package attrs
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+// Redacted is the text that is written in place of a secret value.
+const Redacted = "***"
+
+// SecretValue holds a value that must never be written to a log. However it
+// is formatted, whether with fmt, encoding/json, encoding.TextMarshaler or
+// log/slog, it renders as Redacted.
+type SecretValue struct {
+	value any
+}
+
+// Secret creates an attribute with a value that always renders as Redacted.
+// The value of the attribute is a SecretValue, from which the original value
+// can be retrieved with Reveal.
+func Secret(k string, v any) AttrValue[any] {
+	return attr[any]{key: k, value: SecretValue{value: v}}
+}
+
+// Reveal returns the secret value.
+func (s SecretValue) Reveal() any {
+	return s.value
+}
+
+// String implements fmt.Stringer.
+func (SecretValue) String() string {
+	return Redacted
+}
+
+// GoString implements fmt.GoStringer.
+func (SecretValue) GoString() string {
+	return Redacted
+}
+
+// Format implements fmt.Formatter, so that no verb or flag can print the
+// secret value.
+func (SecretValue) Format(f fmt.State, verb rune) {
+	if verb == 'q' {
+		fmt.Fprintf(f, "%q", Redacted)
+		return
+	}
+	fmt.Fprint(f, Redacted)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (SecretValue) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (SecretValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted)
+}
+
+// LogValue implements slog.LogValuer.
+func (SecretValue) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
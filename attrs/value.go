@@ -0,0 +1,459 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package attrs
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"unsafe"
+)
+
+// Kind is the kind of a Value.
+type Kind int
+
+// The kinds of Value. New kinds may be added, so writers should render kinds
+// they don't handle with Value.String.
+const (
+	KindAny Kind = iota
+	KindString
+	KindInt64
+	KindUint64
+	KindFloat64
+	KindBool
+	KindTime
+	KindDuration
+	KindStrings
+	KindInts
+	KindStringMap
+	KindBytes
+	KindStringer
+	KindError
+	KindGroup
+)
+
+var kindNames = []string{
+	KindAny:       "Any",
+	KindString:    "String",
+	KindInt64:     "Int64",
+	KindUint64:    "Uint64",
+	KindFloat64:   "Float64",
+	KindBool:      "Bool",
+	KindTime:      "Time",
+	KindDuration:  "Duration",
+	KindStrings:   "Strings",
+	KindInts:      "Ints",
+	KindStringMap: "StringMap",
+	KindBytes:     "Bytes",
+	KindStringer:  "Stringer",
+	KindError:     "Error",
+	KindGroup:     "Group",
+}
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	if k >= 0 && int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "<unknown attrs.Kind>"
+}
+
+// Attr is a key and value, the normalised form of a typed attribute returned
+// by Normalize.
+type Attr struct {
+	Key   string
+	Value Value
+}
+
+// String returns the key and value separated by an equals sign.
+func (a Attr) String() string {
+	return a.Key + "=" + a.Value.String()
+}
+
+// Value holds the value of an attribute, which can be retrieved with the
+// accessor for its Kind. The accessors panic if called for a different kind.
+//
+// Like slog.Value, a Value is a compact union that holds numbers, strings,
+// times, durations, slices and groups without allocating. It is only the
+// normalised form of attributes: the attributes held by a log entry are
+// still the typed attributes created by the constructors, each of which is
+// boxed in an interface when it is created. When an entry is written to
+// several writers, its attributes are normalised once and shared by them.
+type Value struct {
+	_ [0]func() // Values are not comparable.
+	// num holds the bits of numbers, bools, durations and times, and the
+	// length of strings, slices and groups. For the kinds held in any, it
+	// holds the kind.
+	num uint64
+	// any holds the kind of numbers, bools and durations, a pointer to the
+	// data of strings, slices and groups, the location of times, or the
+	// value itself for the other kinds.
+	any any
+}
+
+type (
+	// kind is held in any for the kinds held entirely in num. It is a
+	// distinct type, so that it can't be confused with a Kind held as the
+	// value of a Stringer or Any value.
+	kind         Kind
+	stringptr    *byte
+	stringsptr   *string
+	intsptr      *int
+	bytesptr     *byte
+	groupptr     *Attr
+	timeLocation *time.Location
+	timeTime     time.Time
+)
+
+// StringValue returns a Value for a string.
+func StringValue(v string) Value {
+	return Value{num: uint64(len(v)), any: stringptr(unsafe.StringData(v))}
+}
+
+// Int64Value returns a Value for an int64.
+func Int64Value(v int64) Value {
+	return Value{num: uint64(v), any: kind(KindInt64)}
+}
+
+// Uint64Value returns a Value for a uint64.
+func Uint64Value(v uint64) Value {
+	return Value{num: v, any: kind(KindUint64)}
+}
+
+// Float64Value returns a Value for a float64.
+func Float64Value(v float64) Value {
+	return Value{num: math.Float64bits(v), any: kind(KindFloat64)}
+}
+
+// BoolValue returns a Value for a bool.
+func BoolValue(v bool) Value {
+	var num uint64
+	if v {
+		num = 1
+	}
+	return Value{num: num, any: kind(KindBool)}
+}
+
+// TimeValue returns a Value for a time.Time. The monotonic clock reading of
+// the time is discarded.
+func TimeValue(v time.Time) Value {
+	if v.IsZero() {
+		// The zero time can't be represented in nanoseconds since the
+		// epoch, so it is held as it is.
+		return Value{any: timeTime(time.Time{})}
+	}
+	nanos := v.UnixNano()
+	if !v.Equal(time.Unix(0, nanos)) {
+		// The time is out of the range of nanoseconds since the epoch.
+		return Value{any: timeTime(v.Round(0))}
+	}
+	return Value{num: uint64(nanos), any: timeLocation(v.Location())}
+}
+
+// DurationValue returns a Value for a time.Duration.
+func DurationValue(v time.Duration) Value {
+	return Value{num: uint64(v), any: kind(KindDuration)}
+}
+
+// StringsValue returns a Value for a []string.
+func StringsValue(v []string) Value {
+	return Value{num: uint64(len(v)), any: stringsptr(unsafe.SliceData(v))}
+}
+
+// IntsValue returns a Value for a []int.
+func IntsValue(v []int) Value {
+	return Value{num: uint64(len(v)), any: intsptr(unsafe.SliceData(v))}
+}
+
+// StringMapValue returns a Value for a map[string]string.
+func StringMapValue(v map[string]string) Value {
+	return Value{num: uint64(KindStringMap), any: v}
+}
+
+// BytesValue returns a Value for a []byte.
+func BytesValue(v []byte) Value {
+	return Value{num: uint64(len(v)), any: bytesptr(unsafe.SliceData(v))}
+}
+
+// StringerValue returns a Value for a fmt.Stringer.
+func StringerValue(v fmt.Stringer) Value {
+	return Value{num: uint64(KindStringer), any: v}
+}
+
+// ErrorValue returns a Value for an error.
+func ErrorValue(v error) Value {
+	return Value{num: uint64(KindError), any: v}
+}
+
+// GroupValue returns a Value for a group of attributes.
+func GroupValue(v ...Attr) Value {
+	return Value{num: uint64(len(v)), any: groupptr(unsafe.SliceData(v))}
+}
+
+// AnyValue returns a Value of KindAny for any other value.
+func AnyValue(v any) Value {
+	return Value{num: uint64(KindAny), any: v}
+}
+
+// Kind returns the kind of the value.
+func (v Value) Kind() Kind {
+	switch x := v.any.(type) {
+	case kind:
+		return Kind(x)
+	case stringptr:
+		return KindString
+	case timeLocation, timeTime:
+		return KindTime
+	case stringsptr:
+		return KindStrings
+	case intsptr:
+		return KindInts
+	case bytesptr:
+		return KindBytes
+	case groupptr:
+		return KindGroup
+	default:
+		return Kind(v.num)
+	}
+}
+
+// Any returns the value as an any, whatever its kind.
+func (v Value) Any() any {
+	switch v.Kind() {
+	case KindString:
+		return v.str()
+	case KindInt64:
+		return int64(v.num)
+	case KindUint64:
+		return v.num
+	case KindFloat64:
+		return v.float()
+	case KindBool:
+		return v.num == 1
+	case KindTime:
+		return v.time()
+	case KindDuration:
+		return time.Duration(int64(v.num))
+	case KindStrings:
+		return v.Strings()
+	case KindInts:
+		return v.Ints()
+	case KindBytes:
+		return v.Bytes()
+	case KindGroup:
+		return v.Group()
+	default:
+		return v.any
+	}
+}
+
+// Int64 returns the value of a KindInt64 value.
+func (v Value) Int64() int64 {
+	v.check(KindInt64)
+	return int64(v.num)
+}
+
+// Uint64 returns the value of a KindUint64 value.
+func (v Value) Uint64() uint64 {
+	v.check(KindUint64)
+	return v.num
+}
+
+// Float64 returns the value of a KindFloat64 value.
+func (v Value) Float64() float64 {
+	v.check(KindFloat64)
+	return v.float()
+}
+
+// Bool returns the value of a KindBool value.
+func (v Value) Bool() bool {
+	v.check(KindBool)
+	return v.num == 1
+}
+
+// Time returns the value of a KindTime value.
+func (v Value) Time() time.Time {
+	v.check(KindTime)
+	return v.time()
+}
+
+// Duration returns the value of a KindDuration value.
+func (v Value) Duration() time.Duration {
+	v.check(KindDuration)
+	return time.Duration(int64(v.num))
+}
+
+// Strings returns the value of a KindStrings value.
+func (v Value) Strings() []string {
+	v.check(KindStrings)
+	return unsafe.Slice((*string)(v.any.(stringsptr)), v.num)
+}
+
+// Ints returns the value of a KindInts value.
+func (v Value) Ints() []int {
+	v.check(KindInts)
+	return unsafe.Slice((*int)(v.any.(intsptr)), v.num)
+}
+
+// StringMap returns the value of a KindStringMap value.
+func (v Value) StringMap() map[string]string {
+	v.check(KindStringMap)
+	return v.any.(map[string]string)
+}
+
+// Bytes returns the value of a KindBytes value.
+func (v Value) Bytes() []byte {
+	v.check(KindBytes)
+	return unsafe.Slice((*byte)(v.any.(bytesptr)), v.num)
+}
+
+// Stringer returns the value of a KindStringer value.
+func (v Value) Stringer() fmt.Stringer {
+	v.check(KindStringer)
+	s, _ := v.any.(fmt.Stringer)
+	return s
+}
+
+// Error returns the value of a KindError value.
+func (v Value) Error() error {
+	v.check(KindError)
+	err, _ := v.any.(error)
+	return err
+}
+
+// Group returns the attributes of a KindGroup value.
+func (v Value) Group() []Attr {
+	v.check(KindGroup)
+	return unsafe.Slice((*Attr)(v.any.(groupptr)), v.num)
+}
+
+// String returns the value of a KindString value, or a rendering of the value
+// for the other kinds, so that it can be used by writers for kinds that they
+// don't handle specially. Slices and maps of strings are quoted, bytes are
+// hex encoded and errors are rendered with their message.
+func (v Value) String() string {
+	switch v.Kind() {
+	case KindString:
+		return v.str()
+	case KindInt64:
+		return strconv.FormatInt(int64(v.num), 10)
+	case KindUint64:
+		return strconv.FormatUint(v.num, 10)
+	case KindFloat64:
+		return strconv.FormatFloat(v.float(), 'g', -1, 64)
+	case KindBool:
+		return strconv.FormatBool(v.num == 1)
+	case KindStrings:
+		return fmt.Sprintf("%q", v.Strings())
+	case KindStringMap:
+		return fmt.Sprintf("%q", v.any)
+	case KindBytes:
+		return hex.EncodeToString(v.Bytes())
+	case KindError:
+		if v.any == nil {
+			return "<nil>"
+		}
+		return v.any.(error).Error()
+	default:
+		return fmt.Sprint(v.Any())
+	}
+}
+
+func (v Value) str() string {
+	return unsafe.String((*byte)(v.any.(stringptr)), v.num)
+}
+
+func (v Value) float() float64 {
+	return math.Float64frombits(v.num)
+}
+
+func (v Value) time() time.Time {
+	if t, ok := v.any.(timeTime); ok {
+		return time.Time(t)
+	}
+	return time.Unix(0, int64(v.num)).In(v.any.(timeLocation))
+}
+
+func (v Value) check(expected Kind) {
+	if k := v.Kind(); k != expected {
+		panic(fmt.Sprintf("attrs: Value kind is %s, not %s", k, expected))
+	}
+}
+
+// Normalize returns the key and value of a typed attribute, reporting whether
+// the attribute is of a known type. Writers can use it to handle every type
+// of attribute by its Kind, rather than by its Go type. The values of Any
+// attributes are resolved with Resolve, and the attributes of groups are
+// normalised too, leaving out any of an unknown type.
+func Normalize(attr any) (Attr, bool) {
+	switch a := attr.(type) {
+	case AttrValue[string]:
+		return Attr{Key: a.Key(), Value: StringValue(a.Value())}, true
+	case AttrValue[int]:
+		return Attr{Key: a.Key(), Value: Int64Value(int64(a.Value()))}, true
+	case AttrValue[int64]:
+		return Attr{Key: a.Key(), Value: Int64Value(a.Value())}, true
+	case AttrValue[uint64]:
+		return Attr{Key: a.Key(), Value: Uint64Value(a.Value())}, true
+	case AttrValue[float64]:
+		return Attr{Key: a.Key(), Value: Float64Value(a.Value())}, true
+	case AttrValue[bool]:
+		return Attr{Key: a.Key(), Value: BoolValue(a.Value())}, true
+	case AttrValue[time.Time]:
+		return Attr{Key: a.Key(), Value: TimeValue(a.Value())}, true
+	case AttrValue[time.Duration]:
+		return Attr{Key: a.Key(), Value: DurationValue(a.Value())}, true
+	case AttrValue[[]string]:
+		return Attr{Key: a.Key(), Value: StringsValue(a.Value())}, true
+	case AttrValue[[]int]:
+		return Attr{Key: a.Key(), Value: IntsValue(a.Value())}, true
+	case AttrValue[map[string]string]:
+		return Attr{Key: a.Key(), Value: StringMapValue(a.Value())}, true
+	case AttrValue[[]byte]:
+		return Attr{Key: a.Key(), Value: BytesValue(a.Value())}, true
+	case AttrValue[fmt.Stringer]:
+		return Attr{Key: a.Key(), Value: StringerValue(a.Value())}, true
+	case AttrValue[error]:
+		return Attr{Key: a.Key(), Value: ErrorValue(a.Value())}, true
+	case AttrValue[any]:
+		return Attr{Key: a.Key(), Value: AnyValue(Resolve(a.Value()))}, true
+	case AttrValue[[]any]:
+		members := make([]Attr, 0, len(a.Value()))
+		for _, member := range a.Value() {
+			if m, ok := Normalize(member); ok {
+				members = append(members, m)
+			}
+		}
+		return Attr{Key: a.Key(), Value: GroupValue(members...)}, true
+	}
+	return Attr{}, false
+}
+
+// isAttr reports whether v is a typed attribute, one that Normalize accepts.
+// It doesn't call Value, so that lazy attributes aren't evaluated.
+func isAttr(v any) bool {
+	switch v.(type) {
+	case AttrValue[string]:
+	case AttrValue[int]:
+	case AttrValue[int64]:
+	case AttrValue[uint64]:
+	case AttrValue[float64]:
+	case AttrValue[bool]:
+	case AttrValue[time.Time]:
+	case AttrValue[time.Duration]:
+	case AttrValue[[]string]:
+	case AttrValue[[]int]:
+	case AttrValue[map[string]string]:
+	case AttrValue[[]byte]:
+	case AttrValue[fmt.Stringer]:
+	case AttrValue[any]:
+	case AttrValue[[]any]:
+	case AttrValue[error]:
+	default:
+		return false
+	}
+	return true
+}
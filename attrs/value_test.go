@@ -0,0 +1,134 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package attrs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNormalize(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		attr   any
+		kind   Kind
+		string string
+	}{
+		{String("k", "v"), KindString, "v"},
+		{Int("k", -1), KindInt64, "-1"},
+		{Int64("k", 2), KindInt64, "2"},
+		{Uint64("k", 3), KindUint64, "3"},
+		{Float64("k", 1.5), KindFloat64, "1.5"},
+		{Bool("k", true), KindBool, "true"},
+		{Time("k", now), KindTime, now.String()},
+		{Duration("k", time.Second), KindDuration, "1s"},
+		{Strings("k", []string{"a b", "c"}), KindStrings, `["a b" "c"]`},
+		{Ints("k", []int{1, 2}), KindInts, "[1 2]"},
+		{StringMap("k", map[string]string{"a": "b"}), KindStringMap, `map["a":"b"]`},
+		{Bytes("k", []byte{0xca, 0xfe}), KindBytes, "cafe"},
+		{Stringer("k", time.Minute), KindStringer, "1m0s"},
+		{Err(errors.New("boom")), KindError, "boom"},
+		{Any("k", []float64{1}), KindAny, "[1]"},
+		{Secret("k", "hunter2"), KindAny, Redacted},
+		{Lazy("k", func() int { return 7 }), KindAny, "7"},
+	}
+	for _, test := range tests {
+		a, ok := Normalize(test.attr)
+		if !ok {
+			t.Errorf("%#v: expected attr to be normalised", test.attr)
+			continue
+		}
+		if a.Value.Kind() != test.kind {
+			t.Errorf("%#v: expected kind %s, got %s", test.attr, test.kind, a.Value.Kind())
+		}
+		if a.Value.String() != test.string {
+			t.Errorf("%#v: expected %q, got %q", test.attr, test.string, a.Value.String())
+		}
+	}
+	if _, ok := Normalize("not an attr"); ok {
+		t.Errorf("expected plain value not to be normalised")
+	}
+}
+
+func TestNormalizeGroup(t *testing.T) {
+	a, ok := Normalize(Group("http", String("method", "GET"), Int("status", 200)))
+	if !ok || a.Key != "http" || a.Value.Kind() != KindGroup {
+		t.Fatalf("unexpected group %v", a)
+	}
+	members := a.Value.Group()
+	if len(members) != 2 || members[0].String() != "method=GET" || members[1].Value.Int64() != 200 {
+		t.Errorf("unexpected members %v", members)
+	}
+}
+
+func TestValueAccessorPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "attrs: Value kind is String, not Int64" {
+			t.Errorf("unexpected panic %v", r)
+		}
+	}()
+	StringValue("s").Int64()
+}
+
+func TestValidDoesNotEvaluateLazy(t *testing.T) {
+	called := false
+	lazy := Lazy("k", func() string {
+		called = true
+		return "v"
+	})
+	if err := Valid([]any{lazy}); err != nil {
+		t.Fatal(err)
+	}
+	if called {
+		t.Errorf("expected lazy attr not to be evaluated")
+	}
+}
+
+func TestValueRoundTrip(t *testing.T) {
+	now := time.Now()
+	if got := TimeValue(now).Time(); !got.Equal(now) || got.Location() != now.Location() {
+		t.Errorf("expected %v, got %v", now, got)
+	}
+	far := time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := TimeValue(far).Time(); !got.Equal(far) {
+		t.Errorf("expected %v, got %v", far, got)
+	}
+	if got := TimeValue(time.Time{}).Time(); !got.IsZero() {
+		t.Errorf("expected zero time, got %v", got)
+	}
+	if got := Float64Value(-0.25).Float64(); got != -0.25 {
+		t.Errorf("expected -0.25, got %v", got)
+	}
+	if got := Int64Value(-5).Int64(); got != -5 {
+		t.Errorf("expected -5, got %v", got)
+	}
+	if got := StringValue("").String(); got != "" {
+		t.Errorf("expected empty string, got %q", got)
+	}
+	if got := StringsValue(nil).Strings(); len(got) != 0 {
+		t.Errorf("expected no strings, got %q", got)
+	}
+	// A Kind held as a Stringer is not confused with the kind of the value.
+	if v := StringerValue(KindInt64); v.Kind() != KindStringer || v.String() != "Int64" {
+		t.Errorf("unexpected value %s of kind %s", v, v.Kind())
+	}
+}
+
+func TestValueDoesNotAllocate(t *testing.T) {
+	s := "value"
+	ints := []int{1, 2}
+	now := time.Now()
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = StringValue(s).String()
+		_ = Int64Value(1).Int64()
+		_ = Float64Value(1.5).Float64()
+		_ = TimeValue(now).Time()
+		_ = DurationValue(time.Second).Duration()
+		_ = IntsValue(ints).Ints()
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
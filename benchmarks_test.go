@@ -4,11 +4,15 @@
 package loggo_test
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"os"
+	"sync/atomic"
 	"testing"
 
 	"github.com/juju/loggo/v3"
+	"github.com/juju/loggo/v3/attrs"
 	"github.com/juju/tc"
 )
 
@@ -102,6 +106,202 @@ func BenchmarkLoggingDiskWriterNoMessagesLogLevel(b *testing.B) {
 		tc.Commentf("Data was written to the log file."))
 }
 
+func BenchmarkLoggingParallelDiscardWriter(b *testing.B) {
+	c := &tc.TBC{TB: b}
+	logger, _ := setupTest(c)
+
+	_, _ = loggo.RemoveWriter("test")
+	err := loggo.RegisterWriter("discard", loggo.NewSimpleWriter(io.Discard, loggo.DefaultFormatter))
+	c.Assert(err, tc.IsNil)
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = logger.Warningf(b.Context(), "just a simple warning")
+		}
+	})
+}
+
+func BenchmarkLoggingParallelCountingWriters(b *testing.B) {
+	c := &tc.TBC{TB: b}
+	logger, _ := setupTest(c)
+
+	_, _ = loggo.RemoveWriter("test")
+	counters := make([]*countingWriter, 3)
+	for i := range counters {
+		counters[i] = &countingWriter{}
+		err := loggo.RegisterWriter(fmt.Sprintf("counter-%d", i), counters[i])
+		c.Assert(err, tc.IsNil)
+	}
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = logger.Warningf(b.Context(), "just a simple warning")
+		}
+	})
+	for _, counter := range counters {
+		c.Assert(counter.count.Load(), tc.Equals, int64(b.N))
+	}
+}
+
+func BenchmarkLoggingParallelSerialWriter(b *testing.B) {
+	c := &tc.TBC{TB: b}
+	logger, _ := setupTest(c)
+
+	_, _ = loggo.RemoveWriter("test")
+	counter := &countingWriter{}
+	err := loggo.RegisterWriter("counter", loggo.NewSerialWriter(counter))
+	c.Assert(err, tc.IsNil)
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = logger.Warningf(b.Context(), "just a simple warning")
+		}
+	})
+	c.Assert(counter.count.Load(), tc.Equals, int64(b.N))
+}
+
+func BenchmarkLoggingDisabledDeepHierarchy(b *testing.B) {
+	context := loggo.NewContext(loggo.WARNING)
+	logger := context.GetLogger("a.b.c.d.e.f.g.h.i.j")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = logger.Tracef(b.Context(), "just a simple trace for %d", i)
+	}
+}
+
+func BenchmarkIsLevelEnabledDeepHierarchy(b *testing.B) {
+	context := loggo.NewContext(loggo.WARNING)
+	logger := context.GetLogger("a.b.c.d.e.f.g.h.i.j")
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if logger.IsTraceEnabled() {
+				b.Fatal("trace should not be enabled")
+			}
+		}
+	})
+}
+
+func BenchmarkLoggingAllocsNoAttrs(b *testing.B) {
+	c := &tc.TBC{TB: b}
+	logger, _ := setupTest(c)
+
+	_, _ = loggo.RemoveWriter("test")
+	err := loggo.RegisterWriter("counter", &countingWriter{})
+	c.Assert(err, tc.IsNil)
+	logger.SetLogLevel(loggo.INFO)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = logger.Info(b.Context(), "just a simple message")
+	}
+}
+
+// BenchmarkLoggingAllocsAttrs allocates for each typed attr, which is boxed
+// when it is created, and for the slice of args that holds them. The attrs
+// are normalised once for each entry, however many writers format them, so
+// the allocations for each extra writer are only those of its formatting.
+func BenchmarkLoggingAllocsAttrs(b *testing.B) {
+	b.Run("counter", func(b *testing.B) {
+		benchmarkLoggingAllocsAttrs(b, &countingWriter{})
+	})
+	for _, count := range []int{1, 3} {
+		b.Run(fmt.Sprintf("formatters=%d", count), func(b *testing.B) {
+			writers := make([]loggo.Writer, count)
+			for i := range writers {
+				writers[i] = loggo.NewSimpleWriter(io.Discard, loggo.JSONFormatter)
+			}
+			benchmarkLoggingAllocsAttrs(b, writers...)
+		})
+	}
+}
+
+func benchmarkLoggingAllocsAttrs(b *testing.B, writers ...loggo.Writer) {
+	c := &tc.TBC{TB: b}
+	logger, _ := setupTest(c)
+
+	_, _ = loggo.RemoveWriter("test")
+	for i, writer := range writers {
+		err := loggo.RegisterWriter(fmt.Sprintf("writer%d", i), writer)
+		c.Assert(err, tc.IsNil)
+	}
+	logger.SetLogLevel(loggo.INFO)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = logger.Info(b.Context(), "just a simple message", attrs.String("key", "value"), attrs.Int("count", i))
+	}
+}
+
+func BenchmarkLoggingAllocsFormatted(b *testing.B) {
+	c := &tc.TBC{TB: b}
+	logger, _ := setupTest(c)
+
+	_, _ = loggo.RemoveWriter("test")
+	err := loggo.RegisterWriter("discard", loggo.NewSimpleWriter(io.Discard, loggo.DefaultFormatter))
+	c.Assert(err, tc.IsNil)
+	logger.SetLogLevel(loggo.INFO)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = logger.Info(b.Context(), "just a simple message", attrs.String("key", "value"), attrs.Int("count", i))
+	}
+}
+
+func TestLoggingNoAttrsDoesNotAllocate(t *testing.T) {
+	c := &tc.TBC{TB: t}
+	logger, _ := setupTest(c)
+	defer loggo.ResetLogging()
+
+	_, _ = loggo.RemoveWriter("test")
+	counter := &countingWriter{}
+	err := loggo.RegisterWriter("counter", counter)
+	c.Assert(err, tc.IsNil)
+	logger.SetLogLevel(loggo.INFO)
+
+	ctx := context.Background()
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = logger.Info(ctx, "just a simple message")
+		_ = logger.Warningf(ctx, "just a simple warning")
+	})
+	c.Check(allocs, tc.Equals, float64(0))
+	c.Check(counter.count.Load(), tc.Equals, int64(202))
+}
+
+func TestLoggingNormalizesAttrsOnce(t *testing.T) {
+	c := &tc.TBC{TB: t}
+	logger, _ := setupTest(c)
+	defer loggo.ResetLogging()
+
+	_, _ = loggo.RemoveWriter("test")
+	for _, name := range []string{"text", "json", "other"} {
+		formatter := loggo.DefaultFormatter
+		if name == "json" {
+			formatter = loggo.JSONFormatter
+		}
+		err := loggo.RegisterWriter(name, loggo.NewSimpleWriter(io.Discard, formatter))
+		c.Assert(err, tc.IsNil)
+	}
+	logger.SetLogLevel(loggo.INFO)
+
+	calls := 0
+	_ = logger.Info(context.Background(), "message", attrs.Any("valuer", countingValuer{calls: &calls}))
+	c.Check(calls, tc.Equals, 1)
+}
+
+// countingWriter counts the entries written to it without serialising.
+type countingWriter struct {
+	count atomic.Int64
+}
+
+func (w *countingWriter) Concurrent() {}
+
+func (w *countingWriter) Write(context.Context, loggo.Entry) error {
+	w.count.Add(1)
+	return nil
+}
+
 func setupTest(c *tc.TBC) (loggo.Logger, *writer) {
 	loggo.ResetLogging()
 	logger := loggo.GetLogger("test.writer")
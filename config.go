@@ -4,6 +4,8 @@
 package loggo
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -37,21 +39,106 @@ func (c Config) String() string {
 	return strings.Join(entries, ";")
 }
 
+// MarshalText implements encoding.TextMarshaler, encoding the config as a
+// configuration string. YAML libraries that use TextMarshaler encode the
+// config in the same way.
+func (c Config) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing the config with
+// ParseConfigString.
+func (c *Config) UnmarshalText(text []byte) error {
+	config, err := ParseConfigString(string(text))
+	if err != nil {
+		return err
+	}
+	*c = config
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding the config as a
+// configuration string. The string isn't HTML escaped, so that encoders
+// that don't escape HTML write the root module as <root>.
+func (c Config) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	writeJSON(&buf, c.String())
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The config may be either a
+// configuration string, as parsed by ParseConfigString, or an object mapping
+// module names to levels, such as {"<root>": "WARNING", "foo.bar": "DEBUG"}.
+// The levels in an object may also be numbers, and the root module may be
+// the empty name, as they were encoded by earlier versions, such as {"": 4}.
+// A JSON null leaves the config unchanged.
+func (c *Config) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var specification string
+		if err := json.Unmarshal(data, &specification); err != nil {
+			return fmt.Errorf("config should be a string or an object, found %s", data)
+		}
+		return c.UnmarshalText([]byte(specification))
+	}
+
+	var values map[string]Level
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	config := make(Config)
+	for name, level := range values {
+		if name != "" {
+			var err error
+			if name, err = parseConfigName(name); err != nil {
+				return err
+			}
+		}
+		config[name] = level
+	}
+	*c = config
+	return nil
+}
+
 func parseConfigValue(value string) (string, Level, error) {
 	pair := strings.SplitN(value, "=", 2)
 	if len(pair) < 2 {
 		return "", UNSPECIFIED, fmt.Errorf("config value expected '=', found %q", value)
 	}
-	name := strings.TrimSpace(pair[0])
-	if name == "" {
+	if strings.TrimSpace(pair[0]) == "" {
 		return "", UNSPECIFIED, fmt.Errorf("config value %q has missing module name", value)
 	}
+	return parseConfigEntry(pair[0], pair[1])
+}
+
+// parseConfigEntry parses the module name and level of a config entry.
+func parseConfigEntry(name, levelStr string) (string, Level, error) {
+	name, err := parseConfigName(name)
+	if err != nil {
+		return "", UNSPECIFIED, err
+	}
+	levelStr = strings.TrimSpace(levelStr)
+	level, ok := ParseLevel(levelStr)
+	if !ok {
+		return "", UNSPECIFIED, fmt.Errorf("unknown severity level %q", levelStr)
+	}
+	return name, level, nil
+}
+
+// parseConfigName parses the module name, tag or pattern of a config entry.
+func parseConfigName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("config has missing module name")
+	}
 
 	if tag := extractConfigTag(name); tag != "" {
 		if strings.Contains(tag, ".") {
 			// Show the original name and not text potentially extracted config
 			// tag.
-			return "", UNSPECIFIED, fmt.Errorf("config tag should not contain '.', found %q", name)
+			return "", fmt.Errorf("config tag should not contain '.', found %q", name)
 		}
 		// Ensure once the normalised extraction has happened, we put the prefix
 		// back on, so that we don't loose the fact that the config is a tag.
@@ -62,15 +149,18 @@ func parseConfigValue(value string) (string, Level, error) {
 		name = fmt.Sprintf("#%s", tag)
 	}
 
-	levelStr := strings.TrimSpace(pair[1])
-	level, ok := ParseLevel(levelStr)
-	if !ok {
-		return "", UNSPECIFIED, fmt.Errorf("unknown severity level %q", levelStr)
+	if isConfigPattern(name) {
+		for _, segment := range strings.Split(name, ".") {
+			if strings.Contains(segment, "*") && segment != "*" && segment != "**" {
+				return "", fmt.Errorf("config pattern %q should only use '*' and '**' as whole segments", name)
+			}
+		}
 	}
+
 	if name == rootString {
 		name = ""
 	}
-	return name, level, nil
+	return name, nil
 }
 
 // ParseConfigString parses a logger configuration string into a map of logger
@@ -86,10 +176,18 @@ func parseConfigValue(value string) (string, Level, error) {
 // This is equivalent to specifying the level of the root module,
 // so "DEBUG" is equivalent to `<root>=DEBUG`
 //
+// A module name may also be a pattern, where a "*" segment matches any single
+// segment of a module name and a "**" segment matches any number of segments,
+// including none. Patterns apply to all matching modules other than the root,
+// including those created after the configuration is applied. A level set for
+// the exact name or a tag of a module takes precedence over patterns, and of
+// the patterns matching a module, the longest wins.
+//
 // An example specification:
 //
 //	`<root>=ERROR; foo.bar=WARNING`
 //	`[TAG]=ERROR`
+//	`juju.*.api=DEBUG; juju.worker.**=TRACE`
 func ParseConfigString(specification string) (Config, error) {
 	specification = strings.TrimSpace(specification)
 	if specification == "" {
@@ -122,3 +220,38 @@ func extractConfigTag(s string) string {
 	}
 	return ""
 }
+
+// isConfigPattern returns whether the config name is a pattern.
+func isConfigPattern(name string) bool {
+	return strings.Contains(name, "*")
+}
+
+// matchConfigPattern returns whether the module name matches the config
+// pattern.
+func matchConfigPattern(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "."), strings.Split(name, "."))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case "**":
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		case "*":
+			if len(name) == 0 {
+				return false
+			}
+		default:
+			if len(name) == 0 || pattern[0] != name[0] {
+				return false
+			}
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -4,6 +4,8 @@
 package loggo
 
 import (
+	"bytes"
+	"encoding/json"
 	"testing"
 
 	"github.com/juju/tc"
@@ -54,6 +56,17 @@ func (*ConfigSuite) TestParseConfigValue(c *tc.C) {
 	}, {
 		value: "#tag.1 = info",
 		err:   `config tag should not contain '.', found "#tag.1"`,
+	}, {
+		value:  "juju.*.api = debug",
+		module: "juju.*.api",
+		level:  DEBUG,
+	}, {
+		value:  "juju.worker.** = trace",
+		module: "juju.worker.**",
+		level:  TRACE,
+	}, {
+		value: "juju.work* = trace",
+		err:   `config pattern "juju.work*" should only use '*' and '**' as whole segments`,
 	}} {
 		c.Logf("%d: %s", i, test.value)
 		module, level, err := parseConfigValue(test.value)
@@ -116,6 +129,12 @@ func (*ConfigSuite) TestParseConfigurationString(c *tc.C) {
 			"foo":     DEBUG,
 			"foo.bar": CRITICAL,
 		},
+	}, {
+		configuration: "juju.*.api=DEBUG; juju.worker.**=TRACE",
+		expected: Config{
+			"juju.*.api":     DEBUG,
+			"juju.worker.**": TRACE,
+		},
 	}, {
 		configuration: "foo;bar",
 		err:           `config value expected '=', found "foo"`,
@@ -165,8 +184,130 @@ func (*ConfigSuite) TestConfigString(c *tc.C) {
 			"other.module": WARNING,
 		},
 		expected: "<root>=WARNING;module=INFO;other.module=WARNING;sub.module=DEBUG",
+	}, {
+		config: Config{
+			"juju.*.api":     DEBUG,
+			"juju.worker.**": TRACE,
+		},
+		expected: "juju.*.api=DEBUG;juju.worker.**=TRACE",
 	}} {
 		c.Logf("%d: %q", i, test.expected)
 		c.Check(test.config.String(), tc.Equals, test.expected)
 	}
 }
+
+func (*ConfigSuite) TestConfigStringRoundTrip(c *tc.C) {
+	specification := "<root>=WARNING;#tag=INFO;juju.*.api=DEBUG;juju.worker=ERROR;juju.worker.**=TRACE"
+	config, err := ParseConfigString(specification)
+	c.Assert(err, tc.IsNil)
+	c.Check(config.String(), tc.Equals, specification)
+}
+
+func (*ConfigSuite) TestMatchConfigPattern(c *tc.C) {
+	for i, test := range []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{"juju.*.api", "juju.worker.api", true},
+		{"juju.*.api", "juju.api", false},
+		{"juju.*.api", "juju.worker.uniter.api", false},
+		{"juju.*", "juju", false},
+		{"juju.*", "juju.worker", true},
+		{"juju.worker.**", "juju.worker", true},
+		{"juju.worker.**", "juju.worker.uniter.api", true},
+		{"juju.worker.**", "juju.workers", false},
+		{"**.api", "api", true},
+		{"**.api", "juju.worker.api", true},
+		{"**.api", "juju.worker.apis", false},
+		{"juju.**.api", "juju.api", true},
+		{"juju.**.api", "juju.a.b.api", true},
+		{"**", "juju", true},
+	} {
+		c.Logf("%d: %q %q", i, test.pattern, test.name)
+		c.Check(matchConfigPattern(test.pattern, test.name), tc.Equals, test.match)
+	}
+}
+
+func (*ConfigSuite) TestConfigText(c *tc.C) {
+	var config Config
+	err := config.UnmarshalText([]byte("<root>=ERROR; foo.bar=debug"))
+	c.Assert(err, tc.IsNil)
+	c.Check(config, tc.DeepEquals, Config{"": ERROR, "foo.bar": DEBUG})
+
+	text, err := config.MarshalText()
+	c.Assert(err, tc.IsNil)
+	c.Check(string(text), tc.Equals, "<root>=ERROR;foo.bar=DEBUG")
+
+	err = config.UnmarshalText([]byte("foo=unknown"))
+	c.Check(err, tc.ErrorMatches, `unknown severity level "unknown"`)
+}
+
+func (*ConfigSuite) TestConfigJSON(c *tc.C) {
+	var service struct {
+		Logging Config `json:"logging"`
+	}
+	err := json.Unmarshal([]byte(`{"logging": "<root>=ERROR; foo.*=debug"}`), &service)
+	c.Assert(err, tc.IsNil)
+	c.Check(service.Logging, tc.DeepEquals, Config{"": ERROR, "foo.*": DEBUG})
+
+	data, err := service.Logging.MarshalJSON()
+	c.Assert(err, tc.IsNil)
+	c.Check(string(data), tc.Equals, `"<root>=ERROR;foo.*=DEBUG"`)
+
+	// An encoder that doesn't escape HTML writes the root module as it is.
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	c.Assert(encoder.Encode(service), tc.IsNil)
+	c.Check(buf.String(), tc.Equals, `{"logging":"<root>=ERROR;foo.*=DEBUG"}`+"\n")
+
+	err = json.Unmarshal([]byte(`{"logging": null}`), &service)
+	c.Assert(err, tc.IsNil)
+	c.Check(service.Logging, tc.DeepEquals, Config{"": ERROR, "foo.*": DEBUG})
+
+	err = json.Unmarshal([]byte(`{"logging": {"<root>": "error", "#Tag": "info", "foo.bar": "debug"}}`), &service)
+	c.Assert(err, tc.IsNil)
+	c.Check(service.Logging, tc.DeepEquals, Config{"": ERROR, "#tag": INFO, "foo.bar": DEBUG})
+}
+
+func (*ConfigSuite) TestConfigJSONNumbers(c *tc.C) {
+	// Earlier versions encoded the levels of a config as numbers.
+	var config Config
+	err := json.Unmarshal([]byte(`{"": 4, "foo.bar": 2, "#tag": 0}`), &config)
+	c.Assert(err, tc.IsNil)
+	c.Check(config, tc.DeepEquals, Config{"": WARNING, "foo.bar": DEBUG, "#tag": UNSPECIFIED})
+}
+
+func (*ConfigSuite) TestConfigJSONErrors(c *tc.C) {
+	for i, test := range []struct {
+		data string
+		err  string
+	}{{
+		data: `"foo=unknown"`,
+		err:  `unknown severity level "unknown"`,
+	}, {
+		data: `42`,
+		err:  `config should be a string or an object, found 42`,
+	}, {
+		data: `{"foo": "unknown"}`,
+		err:  `unknown severity level "unknown"`,
+	}, {
+		data: `{"foo": 7}`,
+		err:  `unknown severity level 7`,
+	}, {
+		data: `{"foo": true}`,
+		err:  `severity level should be a string or a number, found true`,
+	}, {
+		data: `{" ": "info"}`,
+		err:  `config has missing module name`,
+	}, {
+		data: `{"foo.ba*": "info"}`,
+		err:  `config pattern "foo.ba\*" should only use '\*' and '\*\*' as whole segments`,
+	}} {
+		c.Logf("%d: %s", i, test.data)
+		var config Config
+		err := json.Unmarshal([]byte(test.data), &config)
+		c.Check(err, tc.ErrorMatches, test.err)
+	}
+}
@@ -5,10 +5,13 @@ package loggo
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 // Context produces loggers for a hierarchy of modules. The context holds
@@ -18,15 +21,26 @@ type Context struct {
 
 	// Perhaps have one mutex?
 	// All `modules` variables are managed by the one mutex.
-	modulesMutex     sync.Mutex
-	modules          map[string]*module
-	modulesTagConfig map[string]Level
-
+	modulesMutex         sync.Mutex
+	modules              map[string]*module
+	modulesTagConfig     map[string]Level
+	modulesPatternConfig map[string]Level
+
+	// writersMutex serialises changes to the writers. The writers are
+	// published as an immutable snapshot, so that logging calls can read
+	// them without taking a lock.
 	writersMutex sync.Mutex
-	writers      map[string]Writer
+	writers      atomic.Pointer[writerSet]
+
+	extractorsMutex sync.Mutex
+	extractors      atomic.Pointer[[]ContextExtractor]
+
+	middlewareMutex sync.Mutex
+	middleware      atomic.Pointer[[]Middleware]
 
-	// writeMuxtex is used to serialise write operations.
-	writeMutex sync.Mutex
+	redactor atomic.Pointer[Redactor]
+
+	stackTraceLevel Level
 }
 
 // NewContext returns a new Context with no writers set.
@@ -36,13 +50,15 @@ func NewContext(rootLevel Level) *Context {
 		rootLevel = WARNING
 	}
 	context := &Context{
-		modules:          make(map[string]*module),
-		modulesTagConfig: make(map[string]Level),
-		writers:          make(map[string]Writer),
+		modules:              make(map[string]*module),
+		modulesTagConfig:     make(map[string]Level),
+		modulesPatternConfig: make(map[string]Level),
 	}
+	context.writers.Store(&writerSet{})
 	context.root = &module{
-		level:   rootLevel,
-		context: context,
+		level:          rootLevel,
+		effectiveLevel: rootLevel,
+		context:        context,
 	}
 	context.root.parent = context.root
 	context.modules[""] = context.root
@@ -123,15 +139,30 @@ func (c *Context) getLoggerModule(name string, tags []string) *module {
 		labels[k] = v
 	}
 
+	patternLevel := c.patternLevel(name)
+	effectiveLevel := level
+	if effectiveLevel == UNSPECIFIED {
+		effectiveLevel = patternLevel
+	}
+	if effectiveLevel == UNSPECIFIED {
+		effectiveLevel = parent.effectiveLevel.get()
+	}
+
 	impl = &module{
-		name:       name,
-		level:      level,
-		parent:     parent,
-		context:    c,
-		tags:       tags,
-		tagsLookup: labelMap,
-		labels:     parent.labels,
+		name:           name,
+		level:          level,
+		patternLevel:   patternLevel,
+		effectiveLevel: effectiveLevel,
+		parent:         parent,
+		context:        c,
+		tags:           tags,
+		tagsLookup:     labelMap,
+		labels:         parent.labels,
 	}
+	if attrs := parent.getAttrs(); len(attrs) > 0 {
+		impl.setAttrs(attrs)
+	}
+	parent.children = append(parent.children, impl)
 	c.modules[name] = impl
 	return impl
 }
@@ -151,8 +182,30 @@ func (c *Context) getLoggerModulesByTag(label string) []*module {
 	return modules
 }
 
+// patternLevel returns the level of the longest config pattern that matches
+// the module name, or UNSPECIFIED if there is none. Patterns of the same
+// length are ordered by name, so that the result is deterministic.
+func (c *Context) patternLevel(name string) Level {
+	if name == "" {
+		// Patterns don't apply to the root module.
+		return UNSPECIFIED
+	}
+	var best string
+	level := UNSPECIFIED
+	for pattern, patternLevel := range c.modulesPatternConfig {
+		if !matchConfigPattern(pattern, name) {
+			continue
+		}
+		if level == UNSPECIFIED || len(pattern) > len(best) || (len(pattern) == len(best) && pattern < best) {
+			best, level = pattern, patternLevel
+		}
+	}
+	return level
+}
+
 // Config returns the current configuration of the Loggers. Loggers
-// with UNSPECIFIED level will not be included.
+// with UNSPECIFIED level will not be included. Patterns are included
+// as they were applied.
 func (c *Context) Config() Config {
 	result := make(Config)
 	c.modulesMutex.Lock()
@@ -163,6 +216,9 @@ func (c *Context) Config() Config {
 			result[name] = module.level
 		}
 	}
+	for pattern, level := range c.modulesPatternConfig {
+		result[pattern] = level
+	}
 	return result
 }
 
@@ -176,14 +232,31 @@ func (c *Context) CompleteConfig() Config {
 	for name, module := range c.modules {
 		result[name] = module.level
 	}
+	for pattern, level := range c.modulesPatternConfig {
+		result[pattern] = level
+	}
 	return result
 }
 
 // ApplyConfig configures the logging modules according to the provided config.
+// Patterns are kept, so that they apply to modules created later, until they
+// are applied with an UNSPECIFIED level or the levels are reset.
 func (c *Context) ApplyConfig(config Config) {
 	c.modulesMutex.Lock()
 	defer c.modulesMutex.Unlock()
+	patternsChanged := false
 	for name, level := range config {
+		if isConfigPattern(name) {
+			pattern := strings.ToLower(strings.TrimSpace(name))
+			if level == UNSPECIFIED {
+				delete(c.modulesPatternConfig, pattern)
+			} else {
+				c.modulesPatternConfig[pattern] = level
+			}
+			patternsChanged = true
+			continue
+		}
+
 		tag := extractConfigTag(name)
 		if tag == "" {
 			module := c.getLoggerModule(name, nil)
@@ -200,6 +273,12 @@ func (c *Context) ApplyConfig(config Config) {
 			module.setLevel(level)
 		}
 	}
+	if patternsChanged {
+		for name, module := range c.modules {
+			module.patternLevel.set(c.patternLevel(name))
+		}
+	}
+	c.root.updateEffectiveLevels()
 }
 
 // ResetLoggerLevels iterates through the known logging modules and sets the
@@ -210,98 +289,477 @@ func (c *Context) ResetLoggerLevels() {
 	// Setting the root module to UNSPECIFIED will set it to WARNING.
 	for _, module := range c.modules {
 		module.setLevel(UNSPECIFIED)
+		module.patternLevel.set(UNSPECIFIED)
 	}
+	c.root.updateEffectiveLevels()
 	// We can safely just wipe everything here.
 	c.modulesTagConfig = make(map[string]Level)
+	c.modulesPatternConfig = make(map[string]Level)
 }
 
-func (c *Context) write(ctx context.Context, entry Entry) error {
-	c.writeMutex.Lock()
-	defer c.writeMutex.Unlock()
-	for _, writer := range c.getWriters() {
-		if err := writer.Write(ctx, entry); err != nil {
-			return err
+// setLevel sets the level of the given module, and updates the effective
+// levels of the module and its descendants.
+func (c *Context) setLevel(module *module, level Level) {
+	c.modulesMutex.Lock()
+	defer c.modulesMutex.Unlock()
+	module.setLevel(level)
+	module.updateEffectiveLevels()
+}
+
+// ContextExtractor pulls values, such as request or tenant IDs, out of the
+// context.Context passed to a logging call. The returned labels and attrs are
+// added to the log entry before it is handed to the writers.
+type ContextExtractor func(ctx context.Context) (Labels, []any)
+
+// AddContextExtractor registers an extractor that is called for every entry
+// written through the context. Extractors are called in the order they were
+// added. Labels already present on the entry are not overwritten, so labels
+// from the logger or the logging call take precedence.
+func (c *Context) AddContextExtractor(extractor ContextExtractor) error {
+	if extractor == nil {
+		return fmt.Errorf("extractor cannot be nil")
+	}
+	c.extractorsMutex.Lock()
+	defer c.extractorsMutex.Unlock()
+	var extractors []ContextExtractor
+	if current := c.extractors.Load(); current != nil {
+		extractors = append(extractors, *current...)
+	}
+	extractors = append(extractors, extractor)
+	c.extractors.Store(&extractors)
+	return nil
+}
+
+// ResetContextExtractors removes all the registered context extractors.
+func (c *Context) ResetContextExtractors() {
+	c.extractorsMutex.Lock()
+	defer c.extractorsMutex.Unlock()
+	c.extractors.Store(nil)
+}
+
+// extract applies the registered context extractors to the entry.
+func (c *Context) extract(ctx context.Context, entry *Entry) {
+	extractors := c.extractors.Load()
+	if extractors == nil {
+		return
+	}
+	for _, extractor := range *extractors {
+		labels, attrs := extractor(ctx)
+		for k, v := range labels {
+			if _, found := entry.Labels[k]; found {
+				continue
+			}
+			if entry.Labels == nil {
+				entry.Labels = make(Labels)
+			}
+			entry.Labels[k] = v
 		}
+		entry.Attrs = append(entry.Attrs, attrs...)
+	}
+}
+
+// Middleware is called with each entry written through a Context, after the
+// entry has been built and before it is handed to the writers. It may modify
+// the entry, for example to add labels or rewrite the message, and returns
+// false to drop the entry.
+type Middleware func(ctx context.Context, entry *Entry) bool
+
+// Use adds middleware to the context. Middleware is called in the order it was
+// added, after the context extractors, and the first middleware to return
+// false stops the entry from being written.
+func (c *Context) Use(middleware Middleware) error {
+	if middleware == nil {
+		return fmt.Errorf("middleware cannot be nil")
+	}
+	c.middlewareMutex.Lock()
+	defer c.middlewareMutex.Unlock()
+	var result []Middleware
+	if current := c.middleware.Load(); current != nil {
+		result = append(result, *current...)
 	}
+	result = append(result, middleware)
+	c.middleware.Store(&result)
 	return nil
 }
 
-func (c *Context) getWriters() []Writer {
+// ResetMiddleware removes all the middleware from the context.
+func (c *Context) ResetMiddleware() {
+	c.middlewareMutex.Lock()
+	defer c.middlewareMutex.Unlock()
+	c.middleware.Store(nil)
+}
+
+// applyMiddleware calls the middleware with the entry, returning the entry
+// they modified and whether it should be written. The entry is passed by
+// value, so that it is only moved to the heap when there is middleware to
+// call. Entries without labels have nil labels, so that logging them doesn't
+// allocate, but middleware is always given a map it can add labels to.
+func (c *Context) applyMiddleware(ctx context.Context, entry Entry) (Entry, bool) {
+	middleware := c.middleware.Load()
+	if middleware == nil {
+		return entry, true
+	}
+	modified := entry
+	if modified.Labels == nil {
+		modified.Labels = make(Labels)
+	}
+	for _, mw := range *middleware {
+		if !mw(ctx, &modified) {
+			return Entry{}, false
+		}
+	}
+	return modified, true
+}
+
+// SetRedactor sets the redactor used to mask sensitive data in every entry
+// written through the context. The redactor is applied after the middleware,
+// so that it also sees any labels, attrs or message they add. Passing nil
+// stops entries being redacted.
+func (c *Context) SetRedactor(redactor *Redactor) {
+	c.redactor.Store(redactor)
+}
+
+// SetStackTraceLevel sets the minimum level of the entries that have the stack
+// trace of their log call attached, as Entry.Stack. The stack trace starts at
+// the location of the entry, so the frames of functions marked by Helper are
+// skipped. Stack traces are disabled by UNSPECIFIED, which is the default.
+func (c *Context) SetStackTraceLevel(level Level) {
+	c.stackTraceLevel.set(level)
+}
+
+// StackTraceLevel returns the minimum level of the entries that have a stack
+// trace attached, or UNSPECIFIED if stack traces are disabled.
+func (c *Context) StackTraceLevel() Level {
+	return c.stackTraceLevel.get()
+}
+
+func (c *Context) write(ctx context.Context, entry Entry) error {
+	c.extract(ctx, &entry)
+	entry, ok := c.applyMiddleware(ctx, entry)
+	if !ok {
+		return nil
+	}
+	if redactor := c.redactor.Load(); redactor != nil {
+		redactor.Redact(&entry)
+	}
+
+	set := c.writers.Load()
+	err := set.write(ctx, entry)
+	if err != nil && set.errorHandler != nil {
+		set.errorHandler(err)
+		return nil
+	}
+	return err
+}
+
+// writerSet is an immutable snapshot of the writers registered with a
+// Context. Changes to the writers publish a new snapshot rather than
+// modifying the current one.
+type writerSet struct {
+	writers      []namedWriter
+	fallback     Writer
+	errorHandler func(error)
+}
+
+type namedWriter struct {
+	name   string
+	writer Writer
+	// serial is the writer that is called, which serialises the calls to
+	// writer unless it is a ConcurrentWriter.
+	serial   Writer
+	priority int
+}
+
+// serialise returns the writer to call for the given writer, which
+// serialises the calls unless it is safe for concurrent use.
+func serialise(writer Writer) Writer {
+	if _, ok := writer.(ConcurrentWriter); ok {
+		return writer
+	}
+	return NewSerialWriter(writer)
+}
+
+// write writes the entry to every writer, so that one failing writer doesn't
+// prevent the entry from reaching the others. If any writer fails, the entry
+// is also written to the fallback writer.
+//
+// The calls to each writer are serialised, unless it is a ConcurrentWriter,
+// but different writers may be called at the same time.
+func (s *writerSet) write(ctx context.Context, entry Entry) error {
+	if len(entry.Attrs) > 0 && len(s.writers) > 1 {
+		// The writers share the attrs once they have been normalised.
+		entry.normalized = &normalizedAttrs{source: entry.Attrs}
+	}
+	var errs []error
+	for _, w := range s.writers {
+		if err := w.serial.Write(ctx, entry); err != nil {
+			errs = append(errs, fmt.Errorf("writer %q: %w", w.name, err))
+		}
+	}
+	if len(errs) > 0 && s.fallback != nil {
+		if err := s.fallback.Write(ctx, entry); err != nil {
+			errs = append(errs, fmt.Errorf("fallback writer: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// index returns the index of the named writer, or -1 if there isn't one.
+func (s *writerSet) index(name string) int {
+	for i, w := range s.writers {
+		if w.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// clone returns a copy of the set that can be modified before it is
+// published.
+func (s *writerSet) clone() *writerSet {
+	result := *s
+	result.writers = make([]namedWriter, len(s.writers))
+	copy(result.writers, s.writers)
+	return &result
+}
+
+// updateWriters publishes a modified copy of the current writer set. If the
+// update function returns an error, nothing is published.
+func (c *Context) updateWriters(update func(*writerSet) error) error {
 	c.writersMutex.Lock()
 	defer c.writersMutex.Unlock()
-	var result []Writer
-	for _, writer := range c.writers {
-		result = append(result, writer)
+	set := c.writers.Load().clone()
+	if err := update(set); err != nil {
+		return err
 	}
-	return result
+	c.writers.Store(set)
+	return nil
+}
+
+// SetErrorHandler sets a function that is called with the errors returned by
+// the writers for a logging call. When an error handler is set, the logging
+// call itself returns nil. Setting a nil handler restores the default
+// behaviour of returning the errors to the caller.
+func (c *Context) SetErrorHandler(handler func(error)) {
+	_ = c.updateWriters(func(set *writerSet) error {
+		set.errorHandler = handler
+		return nil
+	})
+}
+
+// SetFallbackWriter sets a writer that is given the entry whenever any of the
+// registered writers fail to write it, so that a broken writer doesn't
+// silently lose log messages. The fallback writer is not called otherwise.
+// Setting a nil writer removes the fallback.
+func (c *Context) SetFallbackWriter(writer Writer) {
+	if writer != nil {
+		writer = serialise(writer)
+	}
+	_ = c.updateWriters(func(set *writerSet) error {
+		set.fallback = writer
+		return nil
+	})
 }
 
 // AddWriter adds a writer to the list to be called for each logging call.
 // The name cannot be empty, and the writer cannot be nil. If an existing
 // writer exists with the specified name, an error is returned.
+//
+// Writers are called in the order they were added, after any writers added
+// with a higher priority. AddWriter uses a priority of zero.
+//
+// The calls to the writer are serialised, unless it implements
+// ConcurrentWriter.
 func (c *Context) AddWriter(name string, writer Writer) error {
+	return c.AddWriterWithPriority(name, writer, 0)
+}
+
+// AddWriterWithPriority adds a writer in the same way as AddWriter, but
+// writers with a higher priority are called before those with a lower
+// priority. Writers with the same priority are called in the order they were
+// added.
+func (c *Context) AddWriterWithPriority(name string, writer Writer, priority int) error {
 	if name == "" {
 		return fmt.Errorf("name cannot be empty")
 	}
 	if writer == nil {
 		return fmt.Errorf("writer cannot be nil")
 	}
-	c.writersMutex.Lock()
-	defer c.writersMutex.Unlock()
-	if _, found := c.writers[name]; found {
-		return fmt.Errorf("context already has a writer named %q", name)
+	return c.updateWriters(func(set *writerSet) error {
+		if set.index(name) >= 0 {
+			return fmt.Errorf("context already has a writer named %q", name)
+		}
+		// Insert after every writer with the same or a higher priority,
+		// keeping the writers ordered by priority then insertion.
+		i := len(set.writers)
+		for i > 0 && set.writers[i-1].priority < priority {
+			i--
+		}
+		set.writers = slices.Insert(set.writers, i, namedWriter{
+			name:     name,
+			writer:   writer,
+			serial:   serialise(writer),
+			priority: priority,
+		})
+		return nil
+	})
+}
+
+// WriterNames returns the names of the context's writers in the order they
+// are called.
+func (c *Context) WriterNames() []string {
+	set := c.writers.Load()
+	result := make([]string, len(set.writers))
+	for i, w := range set.writers {
+		result[i] = w.name
 	}
-	c.writers[name] = writer
-	return nil
+	return result
 }
 
 // Writer returns the named writer if one exists.
 // If there is not a writer with the specified name, nil is returned.
 func (c *Context) Writer(name string) Writer {
-	c.writersMutex.Lock()
-	defer c.writersMutex.Unlock()
-	return c.writers[name]
+	set := c.writers.Load()
+	if i := set.index(name); i >= 0 {
+		return set.writers[i].writer
+	}
+	return nil
 }
 
 // RemoveWriter remotes the specified writer. If a writer is not found with
 // the specified name an error is returned. The writer that was removed is also
 // returned.
 func (c *Context) RemoveWriter(name string) (Writer, error) {
-	c.writersMutex.Lock()
-	defer c.writersMutex.Unlock()
-	reg, found := c.writers[name]
-	if !found {
-		return nil, fmt.Errorf("context has no writer named %q", name)
+	removed, err := c.removeWriter(name)
+	if err != nil {
+		return nil, err
 	}
-	delete(c.writers, name)
-	return reg, nil
+	return removed.writer, nil
+}
+
+func (c *Context) removeWriter(name string) (namedWriter, error) {
+	var removed namedWriter
+	err := c.updateWriters(func(set *writerSet) error {
+		i := set.index(name)
+		if i < 0 {
+			return fmt.Errorf("context has no writer named %q", name)
+		}
+		removed = set.writers[i]
+		set.writers = append(set.writers[:i], set.writers[i+1:]...)
+		return nil
+	})
+	return removed, err
 }
 
 // ReplaceWriter is a convenience method that does the equivalent of RemoveWriter
 // followed by AddWriter with the same name. The replaced writer is returned.
+// The new writer keeps the position and priority of the one it replaces.
 func (c *Context) ReplaceWriter(name string, writer Writer) (Writer, error) {
+	replaced, err := c.replaceWriter(name, writer)
+	if err != nil {
+		return nil, err
+	}
+	return replaced.writer, nil
+}
+
+func (c *Context) replaceWriter(name string, writer Writer) (namedWriter, error) {
 	if name == "" {
-		return nil, fmt.Errorf("name cannot be empty")
+		return namedWriter{}, fmt.Errorf("name cannot be empty")
 	}
 	if writer == nil {
-		return nil, fmt.Errorf("writer cannot be nil")
+		return namedWriter{}, fmt.Errorf("writer cannot be nil")
 	}
-	c.writersMutex.Lock()
-	defer c.writersMutex.Unlock()
-	reg, found := c.writers[name]
-	if !found {
-		return nil, fmt.Errorf("context has no writer named %q", name)
+	var replaced namedWriter
+	err := c.updateWriters(func(set *writerSet) error {
+		i := set.index(name)
+		if i < 0 {
+			return fmt.Errorf("context has no writer named %q", name)
+		}
+		replaced = set.writers[i]
+		set.writers[i].writer = writer
+		set.writers[i].serial = serialise(writer)
+		return nil
+	})
+	return replaced, err
+}
+
+// RemoveAndCloseWriter removes the specified writer in the same way as
+// RemoveWriter, and then closes it if it implements Closer, or flushes it if
+// it implements Flusher. The writer is closed once any calls to it that are
+// in progress have finished, unless it is a ConcurrentWriter.
+func (c *Context) RemoveAndCloseWriter(ctx context.Context, name string) error {
+	removed, err := c.removeWriter(name)
+	if err != nil {
+		return err
 	}
-	oldWriter := reg
-	c.writers[name] = writer
-	return oldWriter, nil
+	return CloseWriter(ctx, removed.serial)
+}
+
+// ReplaceAndCloseWriter replaces the specified writer in the same way as
+// ReplaceWriter, and then closes the replaced writer if it implements Closer,
+// or flushes it if it implements Flusher.
+func (c *Context) ReplaceAndCloseWriter(ctx context.Context, name string, writer Writer) error {
+	replaced, err := c.replaceWriter(name, writer)
+	if err != nil {
+		return err
+	}
+	return CloseWriter(ctx, replaced.serial)
+}
+
+// Flush flushes every writer that implements Flusher, in the order the
+// writers are called, followed by the fallback writer. Every writer is
+// flushed even if some fail, unless the context is done, in which case the
+// remaining writers are skipped.
+func (c *Context) Flush(ctx context.Context) error {
+	return c.writers.Load().each(ctx, FlushWriter)
+}
+
+// Close closes every writer that implements Closer, and flushes those that
+// only implement Flusher, in the order the writers are called, followed by
+// the fallback writer. The writers are removed from the context before they
+// are closed, so later logging calls are not written anywhere. Every writer is
+// closed even if some fail, unless the context is done, in which case the
+// remaining writers are left unclosed.
+func (c *Context) Close(ctx context.Context) error {
+	var closing *writerSet
+	_ = c.updateWriters(func(set *writerSet) error {
+		closing = set.clone()
+		set.writers = nil
+		set.fallback = nil
+		return nil
+	})
+	return closing.each(ctx, CloseWriter)
+}
+
+// each calls the function for every writer in the set and the fallback
+// writer, stopping early if the context is done.
+func (s *writerSet) each(ctx context.Context, fn func(context.Context, Writer) error) error {
+	var errs []error
+	for _, w := range s.writers {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		if err := fn(ctx, w.serial); err != nil {
+			errs = append(errs, fmt.Errorf("writer %q: %w", w.name, err))
+		}
+	}
+	if s.fallback != nil {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		if err := fn(ctx, s.fallback); err != nil {
+			errs = append(errs, fmt.Errorf("fallback writer: %w", err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // ResetWriters is generally only used in testing and removes all the writers.
 func (c *Context) ResetWriters() {
-	c.writersMutex.Lock()
-	defer c.writersMutex.Unlock()
-	c.writers = make(map[string]Writer)
+	_ = c.updateWriters(func(set *writerSet) error {
+		set.writers = nil
+		return nil
+	})
 }
 
 // ConfigureLoggers configures loggers according to the given string
@@ -5,9 +5,15 @@ package loggo_test
 
 import (
 	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/juju/loggo/v3"
+	"github.com/juju/loggo/v3/attrs"
 	"github.com/juju/tc"
 )
 
@@ -343,6 +349,84 @@ func (*ContextSuite) TestApplyConfigLabelsResetLoggerLevels(c *tc.C) {
 		})
 }
 
+func (*ContextSuite) TestEffectiveLevelsFollowAncestors(c *tc.C) {
+	context := loggo.NewContext(loggo.WARNING)
+	deep := context.GetLogger("a.b.c.d", "one")
+	c.Assert(deep.EffectiveLogLevel(), tc.Equals, loggo.WARNING)
+
+	context.ApplyConfig(loggo.Config{"a.b": loggo.DEBUG})
+	c.Check(deep.EffectiveLogLevel(), tc.Equals, loggo.DEBUG)
+	c.Check(deep.IsDebugEnabled(), tc.IsTrue)
+	c.Check(deep.IsTraceEnabled(), tc.IsFalse)
+
+	context.GetLogger("a").SetLogLevel(loggo.TRACE)
+	c.Check(deep.EffectiveLogLevel(), tc.Equals, loggo.DEBUG)
+	context.GetLogger("a.b").SetLogLevel(loggo.UNSPECIFIED)
+	c.Check(deep.EffectiveLogLevel(), tc.Equals, loggo.TRACE)
+
+	context.ApplyConfig(loggo.Config{"#one": loggo.ERROR})
+	c.Check(deep.EffectiveLogLevel(), tc.Equals, loggo.ERROR)
+	c.Check(context.GetLogger("a.b.c").EffectiveLogLevel(), tc.Equals, loggo.TRACE)
+
+	context.ResetLoggerLevels()
+	c.Check(deep.EffectiveLogLevel(), tc.Equals, loggo.WARNING)
+	c.Check(context.GetLogger("a").EffectiveLogLevel(), tc.Equals, loggo.WARNING)
+
+	// Modules created later pick up the effective level of their parent.
+	context.GetLogger("").SetLogLevel(loggo.INFO)
+	c.Check(context.GetLogger("a.b.c.d.e").EffectiveLogLevel(), tc.Equals, loggo.INFO)
+}
+
+func (*ContextSuite) TestApplyConfigPatterns(c *tc.C) {
+	context := loggo.NewContext(loggo.WARNING)
+	api := context.GetLogger("juju.worker.api")
+
+	context.ApplyConfig(loggo.Config{
+		"juju.*.api":     loggo.DEBUG,
+		"juju.worker.**": loggo.TRACE,
+	})
+	// The longest pattern wins.
+	c.Check(api.EffectiveLogLevel(), tc.Equals, loggo.TRACE)
+	// Patterns apply to modules created later.
+	c.Check(context.GetLogger("juju.state.api").EffectiveLogLevel(), tc.Equals, loggo.DEBUG)
+	c.Check(context.GetLogger("juju.worker.uniter").EffectiveLogLevel(), tc.Equals, loggo.TRACE)
+	c.Check(context.GetLogger("juju.state").EffectiveLogLevel(), tc.Equals, loggo.WARNING)
+
+	// Exact names and tags take precedence over patterns.
+	context.ApplyConfig(loggo.Config{"juju.worker.api": loggo.ERROR, "#one": loggo.INFO})
+	c.Check(api.EffectiveLogLevel(), tc.Equals, loggo.ERROR)
+	c.Check(context.GetLogger("juju.worker.tagged", "one").EffectiveLogLevel(), tc.Equals, loggo.INFO)
+
+	c.Check(context.Config(), tc.DeepEquals, loggo.Config{
+		"":                   loggo.WARNING,
+		"juju.worker.api":    loggo.ERROR,
+		"juju.worker.tagged": loggo.INFO,
+		"juju.*.api":         loggo.DEBUG,
+		"juju.worker.**":     loggo.TRACE,
+	})
+
+	// Applying a pattern as UNSPECIFIED removes it.
+	context.ApplyConfig(loggo.Config{"juju.worker.**": loggo.UNSPECIFIED})
+	c.Check(context.GetLogger("juju.worker.uniter").EffectiveLogLevel(), tc.Equals, loggo.WARNING)
+	c.Check(context.GetLogger("juju.worker.other.api").EffectiveLogLevel(), tc.Equals, loggo.WARNING)
+	c.Check(context.GetLogger("juju.state.api").EffectiveLogLevel(), tc.Equals, loggo.DEBUG)
+
+	context.ResetLoggerLevels()
+	c.Check(context.GetLogger("juju.state.api").EffectiveLogLevel(), tc.Equals, loggo.WARNING)
+	c.Check(context.Config(), tc.DeepEquals, loggo.Config{"": loggo.WARNING})
+}
+
+func (*ContextSuite) TestConfigureLoggersPatterns(c *tc.C) {
+	context := loggo.NewContext(loggo.WARNING)
+	err := context.ConfigureLoggers("<root>=ERROR; juju.**=DEBUG")
+	c.Assert(err, tc.IsNil)
+	c.Check(context.GetLogger("juju").EffectiveLogLevel(), tc.Equals, loggo.DEBUG)
+	c.Check(context.GetLogger("juju.a.b").EffectiveLogLevel(), tc.Equals, loggo.DEBUG)
+	// Patterns don't apply to the root module.
+	c.Check(context.GetLogger("").EffectiveLogLevel(), tc.Equals, loggo.ERROR)
+	c.Check(context.GetLogger("other").EffectiveLogLevel(), tc.Equals, loggo.ERROR)
+}
+
 func (*ContextSuite) TestApplyConfigTagsAddative(c *tc.C) {
 	context := loggo.NewContext(loggo.WARNING)
 	context.ApplyConfig(loggo.Config{"#one": loggo.TRACE})
@@ -490,6 +574,76 @@ func (*ContextSuite) TestMultipleWriters(c *tc.C) {
 	checkLogEntries(c, third.Log(), expected)
 }
 
+func (*ContextSuite) TestWritersCalledInOrder(c *tc.C) {
+	var calls []string
+	recorder := func(name string) loggo.Writer {
+		return writerFunc(func(context.Context, loggo.Entry) error {
+			calls = append(calls, name)
+			return nil
+		})
+	}
+	logContext := loggo.NewContext(loggo.TRACE)
+	for _, name := range []string{"first", "second", "third", "fourth", "fifth"} {
+		c.Assert(logContext.AddWriter(name, recorder(name)), tc.IsNil)
+	}
+	c.Assert(logContext.WriterNames(), tc.DeepEquals, []string{"first", "second", "third", "fourth", "fifth"})
+
+	_ = logContext.GetLogger("test").Infof(context.Background(), "message")
+	c.Check(calls, tc.DeepEquals, []string{"first", "second", "third", "fourth", "fifth"})
+}
+
+func (*ContextSuite) TestAddWriterWithPriority(c *tc.C) {
+	var calls []string
+	recorder := func(name string) loggo.Writer {
+		return writerFunc(func(context.Context, loggo.Entry) error {
+			calls = append(calls, name)
+			return nil
+		})
+	}
+	logContext := loggo.NewContext(loggo.TRACE)
+	c.Assert(logContext.AddWriter("default", recorder("default")), tc.IsNil)
+	c.Assert(logContext.AddWriterWithPriority("shipper", recorder("shipper"), -10), tc.IsNil)
+	c.Assert(logContext.AddWriterWithPriority("audit", recorder("audit"), 10), tc.IsNil)
+	c.Assert(logContext.AddWriter("other", recorder("other")), tc.IsNil)
+	c.Assert(logContext.AddWriterWithPriority("audit-copy", recorder("audit-copy"), 10), tc.IsNil)
+
+	expected := []string{"audit", "audit-copy", "default", "other", "shipper"}
+	c.Assert(logContext.WriterNames(), tc.DeepEquals, expected)
+
+	_ = logContext.GetLogger("test").Infof(context.Background(), "message")
+	c.Check(calls, tc.DeepEquals, expected)
+
+	err := logContext.AddWriterWithPriority("audit", recorder("audit"), 0)
+	c.Check(err, tc.ErrorMatches, `context already has a writer named "audit"`)
+}
+
+func (*ContextSuite) TestReplaceWriterKeepsOrder(c *tc.C) {
+	logContext := loggo.NewContext(loggo.TRACE)
+	c.Assert(logContext.AddWriter("first", &writer{}), tc.IsNil)
+	c.Assert(logContext.AddWriterWithPriority("audit", &writer{}, 10), tc.IsNil)
+	c.Assert(logContext.AddWriter("second", &writer{}), tc.IsNil)
+
+	_, err := logContext.ReplaceWriter("audit", &writer{})
+	c.Assert(err, tc.IsNil)
+	_, err = logContext.ReplaceWriter("first", &writer{})
+	c.Assert(err, tc.IsNil)
+	c.Check(logContext.WriterNames(), tc.DeepEquals, []string{"audit", "first", "second"})
+
+	_, err = logContext.RemoveWriter("first")
+	c.Assert(err, tc.IsNil)
+	c.Check(logContext.WriterNames(), tc.DeepEquals, []string{"audit", "second"})
+}
+
+func (*ContextSuite) TestFirstFailureSurfacesFirst(c *tc.C) {
+	logContext := loggo.NewContext(loggo.TRACE)
+	c.Assert(logContext.AddWriter("one", failingWriter("one")), tc.IsNil)
+	c.Assert(logContext.AddWriter("two", failingWriter("two")), tc.IsNil)
+	c.Assert(logContext.AddWriterWithPriority("zero", failingWriter("zero"), 1), tc.IsNil)
+
+	err := logContext.GetLogger("test").Infof(context.Background(), "message")
+	c.Check(err, tc.ErrorMatches, "writer \"zero\": zero\nwriter \"one\": one\nwriter \"two\": two")
+}
+
 func (*ContextSuite) TestWriter(c *tc.C) {
 	first := &writer{name: "first"}
 	second := &writer{name: "second"}
@@ -506,6 +660,463 @@ func (*ContextSuite) TestWriter(c *tc.C) {
 	c.Check(first, tc.Not(tc.Equals), second)
 }
 
+type requestIDKey struct{}
+
+func (*ContextSuite) TestWriterReceivesContext(c *tc.C) {
+	var received []context.Context
+	logContext := loggo.NewContext(loggo.TRACE)
+	err := logContext.AddWriter("ctx", writerFunc(func(ctx context.Context, _ loggo.Entry) error {
+		received = append(received, ctx)
+		return nil
+	}))
+	c.Assert(err, tc.IsNil)
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-1")
+	_ = logContext.GetLogger("test").Infof(ctx, "hello")
+
+	c.Assert(received, tc.HasLen, 1)
+	c.Check(received[0].Value(requestIDKey{}), tc.Equals, "req-1")
+}
+
+func (s *ContextSuite) TestContextExtractor(c *tc.C) {
+	logContext, writer := s.newContextWithTestWriter(c, loggo.TRACE)
+	err := logContext.AddContextExtractor(func(ctx context.Context) (loggo.Labels, []any) {
+		id, ok := ctx.Value(requestIDKey{}).(string)
+		if !ok {
+			return nil, nil
+		}
+		return loggo.Labels{"request-id": id, "foo": "extracted"}, []any{attrs.String("tenant", "acme")}
+	})
+	c.Assert(err, tc.IsNil)
+
+	logger := logContext.GetLogger("test").WithLabels(loggo.Labels{"foo": "bar"})
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-1")
+	_ = logger.Infof(ctx, "with request")
+	_ = logger.Infof(context.Background(), "without request")
+
+	logs := writer.Log()
+	c.Assert(logs, tc.HasLen, 2)
+	c.Check(logs[0].Labels, tc.DeepEquals, loggo.Labels{"request-id": "req-1", "foo": "bar"})
+	c.Assert(logs[0].Attrs, tc.HasLen, 1)
+	c.Check(logs[0].Attrs[0].(attrs.AttrValue[string]).Value(), tc.Equals, "acme")
+	c.Check(logs[1].Labels, tc.DeepEquals, loggo.Labels{"foo": "bar"})
+	c.Check(logs[1].Attrs, tc.HasLen, 0)
+}
+
+func (*ContextSuite) TestAddContextExtractorNil(c *tc.C) {
+	logContext := loggo.NewContext(loggo.DEBUG)
+	err := logContext.AddContextExtractor(nil)
+	c.Assert(err, tc.ErrorMatches, "extractor cannot be nil")
+}
+
+func (s *ContextSuite) TestResetContextExtractors(c *tc.C) {
+	logContext, writer := s.newContextWithTestWriter(c, loggo.TRACE)
+	err := logContext.AddContextExtractor(func(ctx context.Context) (loggo.Labels, []any) {
+		return loggo.Labels{"extracted": "true"}, nil
+	})
+	c.Assert(err, tc.IsNil)
+	logContext.ResetContextExtractors()
+
+	_ = logContext.GetLogger("test").Infof(context.Background(), "message")
+
+	logs := writer.Log()
+	c.Assert(logs, tc.HasLen, 1)
+	c.Check(logs[0].Labels, tc.HasLen, 0)
+}
+
+func (s *ContextSuite) TestMiddleware(c *tc.C) {
+	logContext, writer := s.newContextWithTestWriter(c, loggo.TRACE)
+	err := logContext.AddContextExtractor(func(ctx context.Context) (loggo.Labels, []any) {
+		return loggo.Labels{"extracted": "true"}, nil
+	})
+	c.Assert(err, tc.IsNil)
+	var seen loggo.Labels
+	err = logContext.Use(func(ctx context.Context, entry *loggo.Entry) bool {
+		seen = entry.Labels
+		entry.Labels["static"] = "label"
+		return true
+	})
+	c.Assert(err, tc.IsNil)
+	err = logContext.Use(func(ctx context.Context, entry *loggo.Entry) bool {
+		entry.Message = strings.ToUpper(entry.Message)
+		return true
+	})
+	c.Assert(err, tc.IsNil)
+
+	_ = logContext.GetLogger("test").Infof(context.Background(), "message")
+
+	logs := writer.Log()
+	c.Assert(logs, tc.HasLen, 1)
+	c.Check(logs[0].Message, tc.Equals, "MESSAGE")
+	c.Check(logs[0].Labels, tc.DeepEquals, loggo.Labels{"extracted": "true", "static": "label"})
+	// The extractors have run before the middleware sees the entry.
+	c.Check(seen["extracted"], tc.Equals, "true")
+}
+
+func (s *ContextSuite) TestMiddlewareAddsLabels(c *tc.C) {
+	logContext, writer := s.newContextWithTestWriter(c, loggo.TRACE)
+	err := logContext.Use(func(ctx context.Context, entry *loggo.Entry) bool {
+		entry.Labels["env"] = "prod"
+		return true
+	})
+	c.Assert(err, tc.IsNil)
+
+	// The entry has no labels of its own.
+	_ = logContext.GetLogger("test").Infof(context.Background(), "message")
+
+	logs := writer.Log()
+	c.Assert(logs, tc.HasLen, 1)
+	c.Check(logs[0].Labels, tc.DeepEquals, loggo.Labels{"env": "prod"})
+}
+
+func (s *ContextSuite) TestMiddlewareDropsEntry(c *tc.C) {
+	logContext, writer := s.newContextWithTestWriter(c, loggo.TRACE)
+	called := false
+	err := logContext.Use(func(ctx context.Context, entry *loggo.Entry) bool {
+		return entry.Message != "drop me"
+	})
+	c.Assert(err, tc.IsNil)
+	err = logContext.Use(func(ctx context.Context, entry *loggo.Entry) bool {
+		called = entry.Message == "drop me"
+		return true
+	})
+	c.Assert(err, tc.IsNil)
+
+	logger := logContext.GetLogger("test")
+	err = logger.Infof(context.Background(), "drop me")
+	c.Assert(err, tc.IsNil)
+	_ = logger.Infof(context.Background(), "keep me")
+
+	c.Check(messages(writer.Log()), tc.DeepEquals, []string{"keep me"})
+	c.Check(called, tc.IsFalse)
+}
+
+func (*ContextSuite) TestUseNil(c *tc.C) {
+	logContext := loggo.NewContext(loggo.DEBUG)
+	err := logContext.Use(nil)
+	c.Assert(err, tc.ErrorMatches, "middleware cannot be nil")
+}
+
+func (s *ContextSuite) TestResetMiddleware(c *tc.C) {
+	logContext, writer := s.newContextWithTestWriter(c, loggo.TRACE)
+	err := logContext.Use(func(context.Context, *loggo.Entry) bool {
+		return false
+	})
+	c.Assert(err, tc.IsNil)
+	logContext.ResetMiddleware()
+
+	_ = logContext.GetLogger("test").Infof(context.Background(), "message")
+	c.Check(writer.Log(), tc.HasLen, 1)
+}
+
+func failingWriter(message string) loggo.Writer {
+	return writerFunc(func(context.Context, loggo.Entry) error {
+		return errors.New(message)
+	})
+}
+
+func (*ContextSuite) TestWriteAttemptsAllWriters(c *tc.C) {
+	first := &writer{name: "first"}
+	second := &writer{name: "second"}
+	logContext := loggo.NewContext(loggo.TRACE)
+	c.Assert(logContext.AddWriter("first", first), tc.IsNil)
+	c.Assert(logContext.AddWriter("broken", failingWriter("boom")), tc.IsNil)
+	c.Assert(logContext.AddWriter("also-broken", failingWriter("bang")), tc.IsNil)
+	c.Assert(logContext.AddWriter("second", second), tc.IsNil)
+
+	err := logContext.GetLogger("test").Infof(context.Background(), "message")
+	c.Assert(err, tc.NotNil)
+	c.Check(err.Error(), tc.Contains, `writer "broken": boom`)
+	c.Check(err.Error(), tc.Contains, `writer "also-broken": bang`)
+
+	c.Check(first.Log(), tc.HasLen, 1)
+	c.Check(second.Log(), tc.HasLen, 1)
+}
+
+func (*ContextSuite) TestErrorHandler(c *tc.C) {
+	logContext := loggo.NewContext(loggo.TRACE)
+	c.Assert(logContext.AddWriter("broken", failingWriter("boom")), tc.IsNil)
+
+	var handled []error
+	logContext.SetErrorHandler(func(err error) {
+		handled = append(handled, err)
+	})
+
+	err := logContext.GetLogger("test").Infof(context.Background(), "message")
+	c.Assert(err, tc.IsNil)
+	c.Assert(handled, tc.HasLen, 1)
+	c.Check(handled[0], tc.ErrorMatches, `writer "broken": boom`)
+
+	logContext.SetErrorHandler(nil)
+	err = logContext.GetLogger("test").Infof(context.Background(), "message")
+	c.Check(err, tc.ErrorMatches, `writer "broken": boom`)
+	c.Check(handled, tc.HasLen, 1)
+}
+
+func (s *ContextSuite) TestErrorHandlerCanLog(c *tc.C) {
+	logContext, writer := s.newContextWithTestWriter(c, loggo.TRACE)
+	c.Assert(logContext.AddWriter("broken", failingWriter("boom")), tc.IsNil)
+
+	logger := logContext.GetLogger("test")
+	handling := false
+	logContext.SetErrorHandler(func(err error) {
+		if handling {
+			return
+		}
+		handling = true
+		_ = logger.Errorf(context.Background(), "logging failed: %v", err)
+	})
+
+	err := logger.Infof(context.Background(), "message")
+	c.Assert(err, tc.IsNil)
+	checkLogEntries(c, writer.Log(), []loggo.Entry{
+		{Level: loggo.INFO, Module: "test", Message: "message"},
+		{Level: loggo.ERROR, Module: "test", Message: `logging failed: writer "broken": boom`},
+	})
+}
+
+func (*ContextSuite) TestFallbackWriter(c *tc.C) {
+	working := &writer{name: "working"}
+	fallback := &writer{name: "fallback"}
+	logContext := loggo.NewContext(loggo.TRACE)
+	c.Assert(logContext.AddWriter("working", working), tc.IsNil)
+	logContext.SetFallbackWriter(fallback)
+
+	logger := logContext.GetLogger("test")
+	err := logger.Infof(context.Background(), "all good")
+	c.Assert(err, tc.IsNil)
+	c.Check(fallback.Log(), tc.HasLen, 0)
+
+	c.Assert(logContext.AddWriter("broken", failingWriter("boom")), tc.IsNil)
+	err = logger.Infof(context.Background(), "broken")
+	c.Check(err, tc.ErrorMatches, `writer "broken": boom`)
+	checkLogEntries(c, fallback.Log(), []loggo.Entry{
+		{Level: loggo.INFO, Module: "test", Message: "broken"},
+	})
+	c.Check(working.Log(), tc.HasLen, 2)
+}
+
+func (*ContextSuite) TestFallbackWriterFails(c *tc.C) {
+	logContext := loggo.NewContext(loggo.TRACE)
+	c.Assert(logContext.AddWriter("broken", failingWriter("boom")), tc.IsNil)
+	logContext.SetFallbackWriter(failingWriter("bang"))
+
+	err := logContext.GetLogger("test").Infof(context.Background(), "message")
+	c.Check(err, tc.ErrorMatches, "writer \"broken\": boom\nfallback writer: bang")
+}
+
+func (*ContextSuite) TestConcurrentWritersChanges(c *tc.C) {
+	logContext := loggo.NewContext(loggo.TRACE)
+	first := &writer{name: "first"}
+	c.Assert(logContext.AddWriter("first", first), tc.IsNil)
+	logger := logContext.GetLogger("test")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = logger.Infof(context.Background(), "message %d", j)
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		c.Assert(logContext.AddWriter("other", &writer{}), tc.IsNil)
+		_, err := logContext.ReplaceWriter("other", &writer{})
+		c.Assert(err, tc.IsNil)
+		_, err = logContext.RemoveWriter("other")
+		c.Assert(err, tc.IsNil)
+	}
+	wg.Wait()
+
+	c.Check(first.Log(), tc.HasLen, 400)
+	c.Check(logContext.WriterNames(), tc.DeepEquals, []string{"first"})
+}
+
+func (*ContextSuite) TestWritersSerialised(c *tc.C) {
+	logContext := loggo.NewContext(loggo.TRACE)
+	serial := &overlapWriter{}
+	c.Assert(logContext.AddWriter("serial", serial), tc.IsNil)
+	replaced := &overlapWriter{}
+	c.Assert(logContext.AddWriter("replaced", &writer{}), tc.IsNil)
+	_, err := logContext.ReplaceWriter("replaced", replaced)
+	c.Assert(err, tc.IsNil)
+	c.Check(logContext.Writer("serial"), tc.Equals, loggo.Writer(serial))
+	logger := logContext.GetLogger("test")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				_ = logger.Infof(context.Background(), "message %d", j)
+			}
+		}()
+	}
+	wg.Wait()
+
+	c.Check(serial.overlapped.Load(), tc.IsFalse)
+	c.Check(serial.count.Load(), tc.Equals, int32(400))
+	c.Check(replaced.overlapped.Load(), tc.IsFalse)
+}
+
+func (*ContextSuite) TestConcurrentWriterNotSerialised(c *tc.C) {
+	logContext := loggo.NewContext(loggo.TRACE)
+	concurrent := &meetingWriter{met: make(chan struct{})}
+	concurrent.arrived.Add(2)
+	c.Assert(logContext.AddWriter("concurrent", concurrent), tc.IsNil)
+	logger := logContext.GetLogger("test")
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			errs <- logger.Infof(context.Background(), "message")
+		}()
+	}
+	// Both calls are in Write at the same time.
+	concurrent.arrived.Wait()
+	close(concurrent.met)
+	c.Check(<-errs, tc.IsNil)
+	c.Check(<-errs, tc.IsNil)
+}
+
+// overlapWriter records whether its Write calls ever overlap.
+type overlapWriter struct {
+	active     atomic.Int32
+	count      atomic.Int32
+	overlapped atomic.Bool
+}
+
+func (w *overlapWriter) Write(context.Context, loggo.Entry) error {
+	if w.active.Add(1) > 1 {
+		w.overlapped.Store(true)
+	}
+	runtime.Gosched()
+	w.count.Add(1)
+	w.active.Add(-1)
+	return nil
+}
+
+// meetingWriter is a ConcurrentWriter whose Write calls wait until met is
+// closed, which the test does once the expected number of calls have arrived.
+type meetingWriter struct {
+	arrived sync.WaitGroup
+	met     chan struct{}
+}
+
+func (w *meetingWriter) Concurrent() {}
+
+func (w *meetingWriter) Write(context.Context, loggo.Entry) error {
+	w.arrived.Done()
+	<-w.met
+	return nil
+}
+
+// lifecycleWriter records the calls to Flush and Close in a shared list.
+type lifecycleWriter struct {
+	writer
+	calls *[]string
+	err   error
+}
+
+func (w *lifecycleWriter) Flush(context.Context) error {
+	*w.calls = append(*w.calls, "flush "+w.name)
+	return w.err
+}
+
+func (w *lifecycleWriter) Close(context.Context) error {
+	*w.calls = append(*w.calls, "close "+w.name)
+	return w.err
+}
+
+func (*ContextSuite) TestFlush(c *tc.C) {
+	var calls []string
+	logContext := loggo.NewContext(loggo.TRACE)
+	c.Assert(logContext.AddWriter("first", &lifecycleWriter{writer: writer{name: "first"}, calls: &calls}), tc.IsNil)
+	c.Assert(logContext.AddWriter("plain", &writer{}), tc.IsNil)
+	c.Assert(logContext.AddWriterWithPriority("audit", &lifecycleWriter{writer: writer{name: "audit"}, calls: &calls}, 1), tc.IsNil)
+	logContext.SetFallbackWriter(&lifecycleWriter{writer: writer{name: "fallback"}, calls: &calls})
+
+	err := logContext.Flush(c.Context())
+	c.Assert(err, tc.IsNil)
+	c.Check(calls, tc.DeepEquals, []string{"flush audit", "flush first", "flush fallback"})
+	c.Check(logContext.WriterNames(), tc.DeepEquals, []string{"audit", "first", "plain"})
+}
+
+func (*ContextSuite) TestFlushErrors(c *tc.C) {
+	var calls []string
+	logContext := loggo.NewContext(loggo.TRACE)
+	c.Assert(logContext.AddWriter("first", &lifecycleWriter{writer: writer{name: "first"}, calls: &calls, err: errors.New("boom")}), tc.IsNil)
+	c.Assert(logContext.AddWriter("second", &lifecycleWriter{writer: writer{name: "second"}, calls: &calls}), tc.IsNil)
+
+	err := logContext.Flush(c.Context())
+	c.Check(err, tc.ErrorMatches, `writer "first": boom`)
+	c.Check(calls, tc.DeepEquals, []string{"flush first", "flush second"})
+}
+
+func (*ContextSuite) TestFlushContextDone(c *tc.C) {
+	var calls []string
+	logContext := loggo.NewContext(loggo.TRACE)
+	c.Assert(logContext.AddWriter("first", &lifecycleWriter{writer: writer{name: "first"}, calls: &calls}), tc.IsNil)
+
+	ctx, cancel := context.WithCancel(c.Context())
+	cancel()
+	err := logContext.Flush(ctx)
+	c.Check(err, tc.ErrorIs, context.Canceled)
+	c.Check(calls, tc.HasLen, 0)
+}
+
+func (*ContextSuite) TestClose(c *tc.C) {
+	var calls []string
+	logContext := loggo.NewContext(loggo.TRACE)
+	c.Assert(logContext.AddWriter("first", &lifecycleWriter{writer: writer{name: "first"}, calls: &calls}), tc.IsNil)
+	c.Assert(logContext.AddWriter("second", &lifecycleWriter{writer: writer{name: "second"}, calls: &calls}), tc.IsNil)
+	logContext.SetFallbackWriter(&lifecycleWriter{writer: writer{name: "fallback"}, calls: &calls})
+
+	err := logContext.Close(c.Context())
+	c.Assert(err, tc.IsNil)
+	c.Check(calls, tc.DeepEquals, []string{"close first", "close second", "close fallback"})
+	c.Check(logContext.WriterNames(), tc.HasLen, 0)
+}
+
+func (*ContextSuite) TestRemoveAndCloseWriter(c *tc.C) {
+	var calls []string
+	logContext := loggo.NewContext(loggo.TRACE)
+	c.Assert(logContext.AddWriter("first", &lifecycleWriter{writer: writer{name: "first"}, calls: &calls}), tc.IsNil)
+	c.Assert(logContext.AddWriter("plain", &writer{}), tc.IsNil)
+
+	err := logContext.RemoveAndCloseWriter(c.Context(), "first")
+	c.Assert(err, tc.IsNil)
+	err = logContext.RemoveAndCloseWriter(c.Context(), "plain")
+	c.Assert(err, tc.IsNil)
+	err = logContext.RemoveAndCloseWriter(c.Context(), "unknown")
+	c.Check(err, tc.ErrorMatches, `context has no writer named "unknown"`)
+
+	c.Check(calls, tc.DeepEquals, []string{"close first"})
+	c.Check(logContext.WriterNames(), tc.HasLen, 0)
+}
+
+func (*ContextSuite) TestReplaceAndCloseWriter(c *tc.C) {
+	var calls []string
+	logContext := loggo.NewContext(loggo.TRACE)
+	c.Assert(logContext.AddWriter("first", &lifecycleWriter{writer: writer{name: "first"}, calls: &calls}), tc.IsNil)
+
+	replacement := &lifecycleWriter{writer: writer{name: "replacement"}, calls: &calls}
+	err := logContext.ReplaceAndCloseWriter(c.Context(), "first", replacement)
+	c.Assert(err, tc.IsNil)
+
+	c.Check(calls, tc.DeepEquals, []string{"close first"})
+	c.Check(logContext.Writer("first"), tc.Equals, replacement)
+}
+
+type writerFunc func(context.Context, loggo.Entry) error
+
+func (f writerFunc) Write(ctx context.Context, entry loggo.Entry) error {
+	return f(ctx, entry)
+}
+
 type writer struct {
 	loggo.TestWriter
 	// The name exists to discriminate writer equality.
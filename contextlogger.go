@@ -0,0 +1,27 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package loggo
+
+import "context"
+
+// loggerKey is the context key used to store a Logger.
+type loggerKey struct{}
+
+// NewContextWithLogger returns a copy of ctx that carries the given logger.
+// The logger, including any labels added with WithLabels and its call
+// depth, can be retrieved later with LoggerFromContext.
+func NewContextWithLogger(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// LoggerFromContext returns the logger carried by ctx. If ctx does not carry
+// a logger, the root logger of the default context is returned.
+func LoggerFromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(Logger); ok {
+			return logger
+		}
+	}
+	return defaultContext.GetLogger("")
+}
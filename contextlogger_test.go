@@ -0,0 +1,60 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package loggo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/juju/loggo/v3"
+	"github.com/juju/tc"
+)
+
+type ContextLoggerSuite struct{}
+
+func TestContextLoggerSuite(t *testing.T) {
+	tc.Run(t, &ContextLoggerSuite{})
+}
+
+func (*ContextLoggerSuite) SetUpTest(c *tc.C) {
+	loggo.ResetDefaultContext()
+}
+
+func (*ContextLoggerSuite) TestLoggerFromContext(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	logContext := loggo.NewContext(loggo.INFO)
+	err := logContext.AddWriter("test", writer)
+	c.Assert(err, tc.IsNil)
+
+	logger := logContext.GetLogger("testing").
+		WithLabels(loggo.Labels{"foo": "bar"}).
+		WithCallDepth(3)
+	ctx := loggo.NewContextWithLogger(context.Background(), logger)
+
+	got := loggo.LoggerFromContext(ctx)
+	c.Assert(got, tc.DeepEquals, logger)
+
+	_ = got.Logf(ctx, loggo.INFO, "from context")
+
+	logs := writer.Log()
+	c.Assert(logs, tc.HasLen, 1)
+	c.Check(logs[0].Module, tc.Equals, "testing")
+	c.Check(logs[0].Labels, tc.DeepEquals, loggo.Labels{"foo": "bar"})
+}
+
+func (*ContextLoggerSuite) TestLoggerFromContextOverridesParent(c *tc.C) {
+	first := loggo.GetLogger("first")
+	second := loggo.GetLogger("second")
+
+	ctx := loggo.NewContextWithLogger(context.Background(), first)
+	ctx = loggo.NewContextWithLogger(ctx, second)
+
+	c.Check(loggo.LoggerFromContext(ctx).Name(), tc.Equals, "second")
+}
+
+func (*ContextLoggerSuite) TestLoggerFromContextFallback(c *tc.C) {
+	logger := loggo.LoggerFromContext(context.Background())
+	c.Check(logger, tc.DeepEquals, loggo.GetLogger(""))
+	c.Check(logger.Name(), tc.Equals, "<root>")
+}
@@ -0,0 +1,265 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package loggo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/juju/loggo/v3/attrs"
+)
+
+// RepeatedKey is the key of the attribute that holds the number of folded
+// entries in a repeated message entry.
+const RepeatedKey = "repeated"
+
+// DedupConfig holds the configuration for a deduplicating writer.
+type DedupConfig struct {
+	// Timeout is how long identical entries are folded before the count
+	// of repeats is written, even if the run hasn't been broken or no more
+	// entries are logged. If it is not positive, the count is only written
+	// when the run is broken.
+	Timeout time.Duration
+	// Clock is used to get the current time and to time the timeout. If it
+	// is nil, the wall clock is used.
+	Clock Clock
+	// ErrorHandler, if set, is called with any error returned by the
+	// wrapped writer when the count of repeats is written by the timeout
+	// timer. Errors are otherwise discarded, as there is no logging call to
+	// return them to.
+	ErrorHandler func(error)
+}
+
+// NewDedupWriter returns a Writer that folds consecutive identical entries,
+// those with the same module, level, message and attrs, into one. The first
+// entry of a run is passed on to the provided writer, and when the run is
+// broken or the timeout passes, an entry with the message "last message
+// repeated N times" is written instead of the repeats, in the way that syslog
+// does. The number of repeats is also recorded as the RepeatedKey attribute.
+//
+// Attrs are compared by their normalised key, kind and value, as returned by
+// attrs.Normalize, so attrs of different Go types that hold the same value are
+// the same. Lazy attrs are not evaluated to compare them, as they are only
+// evaluated for entries that are written, so a lazy attr is only the same as
+// itself. Likewise, values that implement attrs.LogValuer are compared
+// without being resolved.
+//
+// The count written when the timeout passes is written from a timer, in its
+// own goroutine, so the calls to the provided writer are serialised.
+func NewDedupWriter(writer Writer, config DedupConfig) Writer {
+	if config.Clock == nil {
+		config.Clock = wallClock{}
+	}
+	return &dedupWriter{
+		writer: NewSerialWriter(writer),
+		config: config,
+	}
+}
+
+type dedupWriter struct {
+	// writer serialises the calls to the provided writer.
+	writer Writer
+	config DedupConfig
+
+	mu sync.Mutex
+	// last is the most recent entry that was passed on or folded.
+	last    Entry
+	hasLast bool
+	repeats int
+	// since is when the current run started, or its repeats were last
+	// reported.
+	since time.Time
+	// timer writes the count of repeats when the timeout passes. It is set
+	// while there are repeats to report.
+	timer Timer
+}
+
+// Concurrent implements ConcurrentWriter.
+func (w *dedupWriter) Concurrent() {}
+
+// Write implements Writer.
+func (w *dedupWriter) Write(ctx context.Context, entry Entry) error {
+	now := w.config.Clock.Now()
+
+	w.mu.Lock()
+	if w.hasLast && sameEntry(w.last, entry) {
+		w.last = entry
+		w.repeats++
+		var (
+			summary Entry
+			report  bool
+		)
+		if w.config.Timeout > 0 && now.Sub(w.since) >= w.config.Timeout {
+			summary, report = w.summarise(now)
+		} else if w.config.Timeout > 0 && w.timer == nil {
+			w.timer = w.config.Clock.AfterFunc(w.since.Add(w.config.Timeout).Sub(now), w.timeout)
+		}
+		w.mu.Unlock()
+		if report {
+			return w.writer.Write(ctx, summary)
+		}
+		return nil
+	}
+	summary, report := w.summarise(now)
+	w.last = entry
+	w.hasLast = true
+	w.since = now
+	w.mu.Unlock()
+
+	var errs []error
+	if report {
+		if err := w.writer.Write(ctx, summary); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := w.writer.Write(ctx, entry); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Flush implements Flusher. The count of any folded entries is written, and
+// the wrapped writer is flushed.
+func (w *dedupWriter) Flush(ctx context.Context) error {
+	if err := w.writeSummary(ctx); err != nil {
+		return err
+	}
+	return FlushWriter(ctx, w.writer)
+}
+
+// Close implements Closer. The count of any folded entries is written, and
+// the wrapped writer is closed.
+func (w *dedupWriter) Close(ctx context.Context) error {
+	w.mu.Lock()
+	w.stopTimer()
+	w.mu.Unlock()
+	if err := w.writeSummary(ctx); err != nil {
+		return err
+	}
+	return CloseWriter(ctx, w.writer)
+}
+
+func (w *dedupWriter) writeSummary(ctx context.Context) error {
+	w.mu.Lock()
+	summary, report := w.summarise(w.config.Clock.Now())
+	w.mu.Unlock()
+	if !report {
+		return nil
+	}
+	return w.writer.Write(ctx, summary)
+}
+
+// timeout is called by the timer to write the count of repeats once the
+// timeout has passed since the run started or was last reported.
+func (w *dedupWriter) timeout() {
+	now := w.config.Clock.Now()
+
+	w.mu.Lock()
+	w.timer = nil
+	var (
+		summary Entry
+		report  bool
+	)
+	if due := w.since.Add(w.config.Timeout); now.Before(due) {
+		// The repeats were reported since the timer was started.
+		if w.repeats > 0 {
+			w.timer = w.config.Clock.AfterFunc(due.Sub(now), w.timeout)
+		}
+	} else {
+		summary, report = w.summarise(now)
+	}
+	w.mu.Unlock()
+
+	if report {
+		if err := w.writer.Write(context.Background(), summary); err != nil && w.config.ErrorHandler != nil {
+			w.config.ErrorHandler(err)
+		}
+	}
+}
+
+func (w *dedupWriter) stopTimer() {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+}
+
+// summarise returns the repeated message entry for the folded entries, if
+// there are any, and resets the count.
+func (w *dedupWriter) summarise(now time.Time) (Entry, bool) {
+	w.stopTimer()
+	if w.repeats == 0 {
+		return Entry{}, false
+	}
+	summary := w.last
+	summary.Timestamp = now
+	summary.Message = fmt.Sprintf("last message repeated %d times", w.repeats)
+	summary.Attrs = []any{attrs.Int(RepeatedKey, w.repeats)}
+	w.repeats = 0
+	w.since = now
+	return summary, true
+}
+
+func sameEntry(a, b Entry) bool {
+	return a.Module == b.Module &&
+		a.Level == b.Level &&
+		a.Message == b.Message &&
+		sameAttrs(a.Attrs, b.Attrs)
+}
+
+func sameAttrs(a, b []any) bool {
+	// A nil and an empty slice are considered the same.
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !sameAttr(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameAttr reports whether two attrs have the same key and value, without
+// evaluating lazy attrs or resolving LogValuers. Values that aren't typed
+// attrs are compared as they are.
+func sameAttr(a, b any) bool {
+	if attrs.IsLazy(a) || attrs.IsLazy(b) {
+		return a == b
+	}
+	ga, okA := a.(attrs.AttrValue[[]any])
+	gb, okB := b.(attrs.AttrValue[[]any])
+	if okA || okB {
+		return okA && okB && ga.Key() == gb.Key() && sameAttrs(ga.Value(), gb.Value())
+	}
+	va, okA := logValuerAttr(a)
+	vb, okB := logValuerAttr(b)
+	if okA || okB {
+		return okA && okB && va.Key() == vb.Key() && reflect.DeepEqual(va.Value(), vb.Value())
+	}
+
+	na, okA := attrs.Normalize(a)
+	nb, okB := attrs.Normalize(b)
+	if !okA || !okB {
+		return okA == okB && reflect.DeepEqual(a, b)
+	}
+	return na.Key == nb.Key &&
+		na.Value.Kind() == nb.Value.Kind() &&
+		reflect.DeepEqual(na.Value.Any(), nb.Value.Any())
+}
+
+// logValuerAttr returns the attr if it holds a LogValuer, which Normalize
+// would resolve.
+func logValuerAttr(attr any) (attrs.AttrValue[any], bool) {
+	a, ok := attr.(attrs.AttrValue[any])
+	if !ok {
+		return nil, false
+	}
+	_, ok = a.Value().(attrs.LogValuer)
+	return a, ok
+}
@@ -0,0 +1,291 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package loggo_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/juju/loggo/v3"
+	"github.com/juju/loggo/v3/attrs"
+	"github.com/juju/tc"
+)
+
+type DedupWriterSuite struct{}
+
+func TestDedupWriterSuite(t *testing.T) {
+	tc.Run(t, &DedupWriterSuite{})
+}
+
+func (*DedupWriterSuite) TestFoldsRepeats(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	clock := newTestClock()
+	dedup := loggo.NewDedupWriter(writer, loggo.DedupConfig{Clock: clock})
+
+	entry := loggo.Entry{Level: loggo.ERROR, Module: "worker", Message: "flapping"}
+	for i := 0; i < 4; i++ {
+		c.Assert(dedup.Write(c.Context(), entry), tc.IsNil)
+	}
+	c.Check(messages(writer.Log()), tc.DeepEquals, []string{"flapping"})
+
+	clock.Advance(time.Second)
+	err := dedup.Write(c.Context(), loggo.Entry{Level: loggo.INFO, Module: "worker", Message: "recovered"})
+	c.Assert(err, tc.IsNil)
+
+	logs := writer.Log()
+	c.Check(messages(logs), tc.DeepEquals, []string{
+		"flapping",
+		"last message repeated 3 times",
+		"recovered",
+	})
+	c.Check(logs[1].Level, tc.Equals, loggo.ERROR)
+	c.Check(logs[1].Module, tc.Equals, "worker")
+	c.Check(logs[1].Timestamp, tc.Equals, clock.Now())
+	c.Assert(logs[1].Attrs, tc.HasLen, 1)
+	c.Check(logs[1].Attrs[0].(attrs.AttrValue[int]).Key(), tc.Equals, loggo.RepeatedKey)
+	c.Check(logs[1].Attrs[0].(attrs.AttrValue[int]).Value(), tc.Equals, 3)
+}
+
+func (*DedupWriterSuite) TestDifferentFieldsBreakRun(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	dedup := loggo.NewDedupWriter(writer, loggo.DedupConfig{Clock: newTestClock()})
+
+	base := loggo.Entry{Level: loggo.INFO, Module: "worker", Message: "message"}
+	otherModule := base
+	otherModule.Module = "other"
+	otherLevel := base
+	otherLevel.Level = loggo.WARNING
+	withAttrs := base
+	withAttrs.Attrs = []any{attrs.Int("id", 1)}
+	otherAttrs := base
+	otherAttrs.Attrs = []any{attrs.Int("id", 2)}
+
+	for _, entry := range []loggo.Entry{base, otherModule, otherLevel, withAttrs, otherAttrs, base} {
+		c.Assert(dedup.Write(c.Context(), entry), tc.IsNil)
+	}
+	c.Check(writer.Log(), tc.HasLen, 6)
+}
+
+func (*DedupWriterSuite) TestSameAttrsFolded(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	dedup := loggo.NewDedupWriter(writer, loggo.DedupConfig{Clock: newTestClock()})
+
+	entry := loggo.Entry{Level: loggo.INFO, Module: "worker", Message: "message"}
+	entry.Attrs = []any{attrs.Int("id", 1), attrs.Any("ids", []int{1, 2})}
+	repeat := entry
+	repeat.Attrs = []any{attrs.Int("id", 1), attrs.Any("ids", []int{1, 2})}
+	noAttrs := loggo.Entry{Level: loggo.INFO, Module: "worker", Message: "none"}
+	emptyAttrs := noAttrs
+	emptyAttrs.Attrs = []any{}
+
+	for _, e := range []loggo.Entry{entry, repeat, noAttrs, emptyAttrs} {
+		c.Assert(dedup.Write(c.Context(), e), tc.IsNil)
+	}
+	c.Check(messages(writer.Log()), tc.DeepEquals, []string{
+		"message",
+		"last message repeated 1 times",
+		"none",
+	})
+}
+
+func (*DedupWriterSuite) TestLazyAttrsNotEvaluated(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	dedup := loggo.NewDedupWriter(writer, loggo.DedupConfig{Clock: newTestClock()})
+
+	calls := 0
+	lazy := attrs.Lazy("id", func() int {
+		calls++
+		return 1
+	})
+	lazyEntry := func(attr any) loggo.Entry {
+		return loggo.Entry{
+			Level:   loggo.INFO,
+			Module:  "worker",
+			Message: "message",
+			Attrs: []any{
+				attr,
+				attrs.Group("request", attr),
+				attrs.Any("valuer", countingValuer{calls: &calls}),
+			},
+		}
+	}
+	other := attrs.Lazy("id", func() int {
+		calls++
+		return 1
+	})
+	// Entries with the same lazy attrs fold, but a lazy attr is only the
+	// same as itself, as comparing the values would evaluate them. Nor are
+	// LogValuers resolved to compare them.
+	for _, attr := range []any{lazy, lazy, lazy, other} {
+		c.Assert(dedup.Write(c.Context(), lazyEntry(attr)), tc.IsNil)
+	}
+	c.Check(messages(writer.Log()), tc.DeepEquals, []string{
+		"message",
+		"last message repeated 2 times",
+		"message",
+	})
+	c.Check(calls, tc.Equals, 0)
+}
+
+func (*DedupWriterSuite) TestTimeout(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	clock := newTestClock()
+	dedup := loggo.NewDedupWriter(writer, loggo.DedupConfig{
+		Timeout: 30 * time.Second,
+		Clock:   clock,
+	})
+
+	entry := loggo.Entry{Level: loggo.ERROR, Module: "worker", Message: "flapping"}
+	_ = dedup.Write(c.Context(), entry)
+	for i := 0; i < 3; i++ {
+		clock.Advance(10 * time.Second)
+		_ = dedup.Write(c.Context(), entry)
+	}
+	clock.Advance(10 * time.Second)
+	_ = dedup.Write(c.Context(), entry)
+	_ = dedup.Write(c.Context(), loggo.Entry{Level: loggo.INFO, Module: "worker", Message: "done"})
+
+	// The repeats at 10s and 20s are reported when the timeout passes at
+	// 30s, and the rest when the run is broken.
+	c.Check(messages(writer.Log()), tc.DeepEquals, []string{
+		"flapping",
+		"last message repeated 2 times",
+		"last message repeated 2 times",
+		"done",
+	})
+	c.Check(clock.pending(), tc.Equals, 0)
+}
+
+func (*DedupWriterSuite) TestTimeoutWithoutMoreEntries(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	clock := newTestClock()
+	dedup := loggo.NewDedupWriter(writer, loggo.DedupConfig{
+		Timeout: 30 * time.Second,
+		Clock:   clock,
+	})
+
+	entry := loggo.Entry{Level: loggo.ERROR, Module: "worker", Message: "flapping"}
+	for i := 0; i < 3; i++ {
+		_ = dedup.Write(c.Context(), entry)
+	}
+	clock.Advance(29 * time.Second)
+	c.Check(messages(writer.Log()), tc.DeepEquals, []string{"flapping"})
+
+	clock.Advance(time.Second)
+	logs := writer.Log()
+	c.Check(messages(logs), tc.DeepEquals, []string{
+		"flapping",
+		"last message repeated 2 times",
+	})
+	c.Check(logs[1].Timestamp, tc.Equals, clock.Now())
+
+	// Nothing more is reported until there are more repeats.
+	clock.Advance(time.Minute)
+	c.Check(writer.Log(), tc.HasLen, 2)
+	c.Check(clock.pending(), tc.Equals, 0)
+}
+
+func (*DedupWriterSuite) TestCloseWritesSummary(c *tc.C) {
+	var calls []string
+	wrapped := &lifecycleWriter{writer: writer{name: "wrapped"}, calls: &calls}
+	dedup := loggo.NewDedupWriter(wrapped, loggo.DedupConfig{Clock: newTestClock()})
+
+	entry := loggo.Entry{Level: loggo.ERROR, Module: "worker", Message: "flapping"}
+	_ = dedup.Write(c.Context(), entry)
+	_ = dedup.Write(c.Context(), entry)
+	err := loggo.CloseWriter(c.Context(), dedup)
+	c.Assert(err, tc.IsNil)
+
+	c.Check(messages(wrapped.Log()), tc.DeepEquals, []string{
+		"flapping",
+		"last message repeated 1 times",
+	})
+	c.Check(calls, tc.DeepEquals, []string{"close wrapped"})
+}
+
+func (*DedupWriterSuite) TestTimeoutWhileLogging(c *tc.C) {
+	// The timer writes the count of repeats while entries are being
+	// written, which must not call the wrapped writer at the same time.
+	wrapped := &unsafeWriter{}
+	dedup := loggo.NewDedupWriter(wrapped, loggo.DedupConfig{Timeout: time.Millisecond})
+
+	entry := loggo.Entry{Level: loggo.ERROR, Module: "worker", Message: "flapping"}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				_ = dedup.Write(context.Background(), entry)
+				if j%20 == 0 {
+					time.Sleep(time.Millisecond)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	c.Assert(loggo.CloseWriter(c.Context(), dedup), tc.IsNil)
+
+	// Every entry is either written or counted as a repeat.
+	total := 0
+	for _, e := range wrapped.entries {
+		if len(e.Attrs) == 0 {
+			total++
+			continue
+		}
+		total += e.Attrs[0].(attrs.AttrValue[int]).Value()
+	}
+	c.Check(total, tc.Equals, 800)
+}
+
+func (*DedupWriterSuite) TestTimeoutErrorHandler(c *tc.C) {
+	clock := newTestClock()
+	var handled []error
+	failing := writerFunc(func(_ context.Context, entry loggo.Entry) error {
+		if entry.Message == "flapping" {
+			return nil
+		}
+		return errors.New("boom")
+	})
+	dedup := loggo.NewDedupWriter(failing, loggo.DedupConfig{
+		Timeout: time.Second,
+		Clock:   clock,
+		ErrorHandler: func(err error) {
+			handled = append(handled, err)
+		},
+	})
+
+	entry := loggo.Entry{Level: loggo.ERROR, Module: "worker", Message: "flapping"}
+	_ = dedup.Write(c.Context(), entry)
+	_ = dedup.Write(c.Context(), entry)
+	clock.Advance(time.Second)
+
+	c.Assert(handled, tc.HasLen, 1)
+	c.Check(handled[0], tc.ErrorMatches, "boom")
+}
+
+// countingValuer is a LogValuer that counts the times it is resolved.
+type countingValuer struct {
+	calls *int
+}
+
+func (v countingValuer) LogValue() slog.Value {
+	*v.calls++
+	return slog.IntValue(1)
+}
+
+// unsafeWriter records the entries written to it without any locking, so
+// that the race detector reports any concurrent calls.
+type unsafeWriter struct {
+	entries []loggo.Entry
+}
+
+func (w *unsafeWriter) Write(_ context.Context, entry loggo.Entry) error {
+	w.entries = append(w.entries, entry)
+	return nil
+}
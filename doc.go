@@ -49,5 +49,22 @@ To make loggo produce colored output, you can do the following,
 having imported github.com/juju/loggo/v3/loggocolor:
 
 	loggo.ReplaceDefaultWriter(loggocolor.NewWriter(os.Stderr))
+
+The default global context can also be configured without code changes, with
+environment variables that are read when the package is initialised:
+
+	LOGGO_CONFIG   a configuration string, as passed to ConfigureLoggers
+	LOGGO_FORMAT   the format of the default writer: text (the default), json
+	               or color, if the program imports loggocolor
+	LOGGO_OUTPUT   where the default writer writes: stderr (the default),
+	               stdout or the path of a file to append to
+
+LOGGO_TIME_FORMAT sets the time format of the text and color formats.
+
+Libraries should log through loggers from a Context of their own, created with
+NewContext, or from one passed in by the program, so that they aren't
+affected by how the program configures the default context. Programs that
+don't want the default context to be configured from the environment can be
+built with the loggo_noenv build tag.
 */
 package loggo
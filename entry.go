@@ -3,7 +3,12 @@
 
 package loggo
 
-import "time"
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/loggo/v3/attrs"
+)
 
 // Entry represents a single log message.
 type Entry struct {
@@ -25,4 +30,80 @@ type Entry struct {
 	PC uintptr
 	// Attrs is the list of attributes associated with the log message.
 	Attrs []any
+	// Stack is the stack trace of the log call, starting with the frame of
+	// the log call itself. It is only captured for entries at or above the
+	// level set with Context.SetStackTraceLevel.
+	Stack []Frame
+
+	// normalized holds the normalised Attrs, shared by the writers that
+	// the entry is written to.
+	normalized *normalizedAttrs
+}
+
+// NormalizedAttrs returns the Attrs of the entry normalised with
+// attrs.Normalize, leaving out any of an unknown type. When a Context writes
+// an entry to more than one writer, its attrs are normalised at most once, by
+// the first writer that asks for them, and the result is shared with the
+// other writers, so it must not be modified. Otherwise, and for entries whose
+// Attrs have been replaced, the attrs are normalised on each call.
+func (e Entry) NormalizedAttrs() []attrs.Attr {
+	return e.normalizedAttrs(nil)
+}
+
+// normalizedAttrs returns the normalised Attrs of the entry, as
+// NormalizedAttrs does, using buf to hold them if they aren't shared.
+func (e Entry) normalizedAttrs(buf []attrs.Attr) []attrs.Attr {
+	if n := e.normalized; n != nil && n.normalizes(e.Attrs) {
+		n.once.Do(func() {
+			n.attrs = normalizeAttrs(n.buf[:0], n.source)
+		})
+		return n.attrs
+	}
+	if len(e.Attrs) == 0 {
+		return nil
+	}
+	if cap(buf) < len(e.Attrs) {
+		buf = make([]attrs.Attr, 0, len(e.Attrs))
+	}
+	return normalizeAttrs(buf[:0], e.Attrs)
+}
+
+// normalizedAttrs holds the normalised form of the attrs of an entry,
+// which is computed when it is first needed.
+type normalizedAttrs struct {
+	once   sync.Once
+	source []any
+	attrs  []attrs.Attr
+	// buf holds the normalised attrs of entries with only a few attrs,
+	// so that they don't need another allocation.
+	buf [4]attrs.Attr
+}
+
+// normalizes reports whether the attrs are those that n was created for.
+// Writers that replace the Attrs of an entry before passing it on don't
+// get the attrs of the original entry.
+func (n *normalizedAttrs) normalizes(source []any) bool {
+	if len(source) != len(n.source) {
+		return false
+	}
+	return len(source) == 0 || &source[0] == &n.source[0]
+}
+
+// normalizeAttrs appends the normalised form of the source attrs to dst.
+func normalizeAttrs(dst []attrs.Attr, source []any) []attrs.Attr {
+	for _, attr := range source {
+		if a, ok := attrs.Normalize(attr); ok {
+			dst = append(dst, a)
+		}
+	}
+	return dst
+}
+
+// Frame is a single frame of a stack trace.
+type Frame struct {
+	// Function is the package path-qualified name of the function.
+	Function string `json:"function"`
+	// File and Line are the location in the function.
+	File string `json:"file"`
+	Line int    `json:"line"`
 }
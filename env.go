@@ -0,0 +1,184 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build !loggo_noenv
+
+package loggo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// pendingFormat records the default writer of a context configured with a
+// format that wasn't registered at the time, so that the writer can be
+// replaced when the format is registered. Packages that register formats
+// import loggo, so they are initialised after the default context is created.
+var pendingFormat struct {
+	sync.Mutex
+	name    string
+	context *Context
+	output  io.Writer
+	file    *os.File
+	writer  Writer
+}
+
+// configureFromEnvironment configures the context from the environment:
+//
+//   - LOGGO_CONFIG is a configuration string applied with ConfigureLoggers.
+//   - LOGGO_FORMAT is the format of the default writer, one of text, which
+//     is the default, json or a format registered with RegisterFormat, such
+//     as color.
+//   - LOGGO_OUTPUT is where the default writer writes, one of stderr, which
+//     is the default, stdout or the path of a file to append to.
+//
+// Invalid values are logged as warnings and ignored. As formats may be
+// registered by packages initialised after loggo, an unknown format is only
+// reported when the first entry is written.
+func configureFromEnvironment(ctx *Context) {
+	format := strings.ToLower(strings.TrimSpace(os.Getenv("LOGGO_FORMAT")))
+	output := strings.TrimSpace(os.Getenv("LOGGO_OUTPUT"))
+	if format != "" || output != "" {
+		configureDefaultWriter(ctx, format, output)
+	}
+
+	if config := os.Getenv("LOGGO_CONFIG"); config != "" {
+		if err := ctx.ConfigureLoggers(config); err != nil {
+			_ = ctx.GetLogger("loggo").Warningf(context.Background(), "ignoring LOGGO_CONFIG: %v", err)
+		}
+	}
+}
+
+func configureDefaultWriter(ctx *Context, format, output string) {
+	var out io.Writer = os.Stderr
+	var file *os.File
+	var outputErr error
+	switch output {
+	case "", "stderr":
+	case "stdout":
+		out = os.Stdout
+	default:
+		file, outputErr = os.OpenFile(output, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if outputErr == nil {
+			out = file
+		}
+	}
+
+	if format == "" {
+		format = "text"
+	}
+	newWriter, ok := formatWriter(format)
+	if !ok {
+		// Use text until the format is registered.
+		newWriter, _ = formatWriter("text")
+	}
+	writer := newFileWriter(newWriter(out), file)
+	if !ok {
+		writer = &unknownFormatWriter{Writer: writer, format: format}
+	}
+	_, _ = ctx.ReplaceWriter(DefaultWriterName, writer)
+
+	pendingFormat.Lock()
+	pendingFormat.name = ""
+	if !ok {
+		pendingFormat.name = format
+		pendingFormat.context = ctx
+		pendingFormat.output = out
+		pendingFormat.file = file
+		pendingFormat.writer = writer
+	}
+	pendingFormat.Unlock()
+
+	// The warning is written with the new writer.
+	if outputErr != nil {
+		_ = ctx.GetLogger("loggo").Warningf(context.Background(), "ignoring LOGGO_OUTPUT: %v", outputErr)
+	}
+}
+
+// formatRegistered replaces the default writer of the context waiting for
+// the format, unless the writer has been replaced in the meantime.
+func formatRegistered(name string) {
+	pendingFormat.Lock()
+	defer pendingFormat.Unlock()
+	if pendingFormat.name != name {
+		return
+	}
+	pendingFormat.name = ""
+	newWriter, ok := formatWriter(name)
+	if !ok {
+		return
+	}
+	ctx := pendingFormat.context
+	if ctx.Writer(DefaultWriterName) != pendingFormat.writer {
+		return
+	}
+	writer := newFileWriter(newWriter(pendingFormat.output), pendingFormat.file)
+	_, _ = ctx.ReplaceWriter(DefaultWriterName, writer)
+}
+
+// unknownFormatWriter writes entries in text while the default writer waits
+// for its format to be registered. Formats are registered when packages are
+// initialised, so by the time the first entry is written, a format that is
+// still waiting is unknown, and a warning is written before the entry.
+type unknownFormatWriter struct {
+	Writer
+	format string
+	once   sync.Once
+}
+
+// Write implements Writer.
+func (w *unknownFormatWriter) Write(ctx context.Context, entry Entry) error {
+	w.once.Do(func() {
+		_ = w.Writer.Write(ctx, Entry{
+			Level:     WARNING,
+			Module:    "loggo",
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("ignoring LOGGO_FORMAT: unknown format %q, using text", w.format),
+		})
+	})
+	return w.Writer.Write(ctx, entry)
+}
+
+// Flush implements Flusher.
+func (w *unknownFormatWriter) Flush(ctx context.Context) error {
+	return FlushWriter(ctx, w.Writer)
+}
+
+// Close implements Closer.
+func (w *unknownFormatWriter) Close(ctx context.Context) error {
+	return CloseWriter(ctx, w.Writer)
+}
+
+// newFileWriter returns the writer, wrapped so that it closes the file when
+// it is closed, if there is a file.
+func newFileWriter(writer Writer, file *os.File) Writer {
+	if file == nil {
+		return writer
+	}
+	return &fileWriter{Writer: writer, file: file}
+}
+
+// fileWriter is a writer that owns the file it writes to.
+type fileWriter struct {
+	Writer
+	file *os.File
+}
+
+// Flush implements Flusher.
+func (w *fileWriter) Flush(ctx context.Context) error {
+	return FlushWriter(ctx, w.Writer)
+}
+
+// Close implements Closer, closing the writer and then the file.
+func (w *fileWriter) Close(ctx context.Context) error {
+	err := CloseWriter(ctx, w.Writer)
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,12 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build loggo_noenv
+
+package loggo
+
+// configureFromEnvironment does nothing, as the loggo_noenv build tag
+// disables configuring the default context from the environment.
+func configureFromEnvironment(*Context) {}
+
+func formatRegistered(string) {}
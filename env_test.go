@@ -0,0 +1,159 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build !loggo_noenv
+
+package loggo_test
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/juju/loggo/v3"
+	"github.com/juju/tc"
+)
+
+type EnvSuite struct {
+	context *loggo.Context
+}
+
+func TestEnvSuite(t *testing.T) {
+	tc.Run(t, &EnvSuite{})
+}
+
+func (s *EnvSuite) SetUpTest(c *tc.C) {
+	c.Cleanup(loggo.SaveFormats())
+	c.Setenv("LOGGO_CONFIG", "")
+	c.Setenv("LOGGO_FORMAT", "")
+	c.Setenv("LOGGO_OUTPUT", "")
+	s.context = loggo.NewContext(loggo.WARNING)
+	err := s.context.AddWriter(loggo.DefaultWriterName, &loggo.TestWriter{})
+	c.Assert(err, tc.IsNil)
+}
+
+func (s *EnvSuite) TestNoEnvironment(c *tc.C) {
+	writer := s.context.Writer(loggo.DefaultWriterName)
+	loggo.ConfigureFromEnvironment(s.context)
+	c.Check(s.context.Writer(loggo.DefaultWriterName), tc.Equals, writer)
+	c.Check(s.context.Config(), tc.DeepEquals, loggo.Config{"": loggo.WARNING})
+}
+
+func (s *EnvSuite) TestConfig(c *tc.C) {
+	c.Setenv("LOGGO_CONFIG", "<root>=INFO; foo.**=TRACE")
+	loggo.ConfigureFromEnvironment(s.context)
+	c.Check(s.context.Config(), tc.DeepEquals, loggo.Config{"": loggo.INFO, "foo.**": loggo.TRACE})
+}
+
+func (s *EnvSuite) TestInvalidConfig(c *tc.C) {
+	writer := s.context.Writer(loggo.DefaultWriterName).(*loggo.TestWriter)
+	c.Setenv("LOGGO_CONFIG", "<root>=LOUD")
+	loggo.ConfigureFromEnvironment(s.context)
+	c.Check(s.context.Config(), tc.DeepEquals, loggo.Config{"": loggo.WARNING})
+
+	log := writer.Log()
+	c.Assert(log, tc.HasLen, 1)
+	c.Check(log[0].Level, tc.Equals, loggo.WARNING)
+	c.Check(log[0].Message, tc.Equals, `ignoring LOGGO_CONFIG: unknown severity level "LOUD"`)
+}
+
+func (s *EnvSuite) TestJSONFileOutput(c *tc.C) {
+	path := filepath.Join(c.MkDir(), "loggo.log")
+	c.Setenv("LOGGO_FORMAT", "JSON")
+	c.Setenv("LOGGO_OUTPUT", path)
+	loggo.ConfigureFromEnvironment(s.context)
+
+	_ = s.context.GetLogger("test").Warningf(c.Context(), "hello")
+	err := s.context.RemoveAndCloseWriter(c.Context(), loggo.DefaultWriterName)
+	c.Assert(err, tc.IsNil)
+
+	data, err := os.ReadFile(path)
+	c.Assert(err, tc.IsNil)
+	var entry struct {
+		Level   string `json:"level"`
+		Module  string `json:"module"`
+		Message string `json:"message"`
+	}
+	c.Assert(json.Unmarshal(data, &entry), tc.IsNil)
+	c.Check(entry.Level, tc.Equals, "WARNING")
+	c.Check(entry.Module, tc.Equals, "test")
+	c.Check(entry.Message, tc.Equals, "hello")
+}
+
+func (s *EnvSuite) TestInvalidOutput(c *tc.C) {
+	c.Setenv("LOGGO_FORMAT", "json")
+	c.Setenv("LOGGO_OUTPUT", filepath.Join(c.MkDir(), "missing", "loggo.log"))
+	stderr := captureStderr(c, func() {
+		loggo.ConfigureFromEnvironment(s.context)
+	})
+	c.Check(stderr, tc.Matches, `\{.*"level":"WARNING","module":"loggo",.*"message":"ignoring LOGGO_OUTPUT: .*no such file or directory"\}\n`)
+}
+
+func (s *EnvSuite) TestUnknownFormat(c *tc.C) {
+	path := filepath.Join(c.MkDir(), "loggo.log")
+	c.Setenv("LOGGO_FORMAT", "jsn")
+	c.Setenv("LOGGO_OUTPUT", path)
+	loggo.ConfigureFromEnvironment(s.context)
+
+	logger := s.context.GetLogger("test")
+	_ = logger.Warningf(c.Context(), "first")
+	_ = logger.Warningf(c.Context(), "second")
+	err := s.context.RemoveAndCloseWriter(c.Context(), loggo.DefaultWriterName)
+	c.Assert(err, tc.IsNil)
+
+	data, err := os.ReadFile(path)
+	c.Assert(err, tc.IsNil)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	c.Assert(lines, tc.HasLen, 3)
+	c.Check(lines[0], tc.Matches, `.* WARNING loggo .* ignoring LOGGO_FORMAT: unknown format "jsn", using text`)
+	c.Check(lines[1], tc.Matches, `.* WARNING test .* first`)
+	c.Check(lines[2], tc.Matches, `.* WARNING test .* second`)
+}
+
+func (s *EnvSuite) TestFormatRegisteredLater(c *tc.C) {
+	c.Setenv("LOGGO_FORMAT", "later")
+	loggo.ConfigureFromEnvironment(s.context)
+	textWriter := s.context.Writer(loggo.DefaultWriterName)
+	c.Check(textWriter, tc.Not(tc.FitsTypeOf), &loggo.TestWriter{})
+
+	var output io.Writer
+	laterWriter := &loggo.TestWriter{}
+	loggo.RegisterFormat("later", func(writer io.Writer) loggo.Writer {
+		output = writer
+		return laterWriter
+	})
+	c.Check(s.context.Writer(loggo.DefaultWriterName), tc.Equals, loggo.Writer(laterWriter))
+	c.Check(output, tc.Equals, io.Writer(os.Stderr))
+}
+
+func (s *EnvSuite) TestFormatRegisteredAfterWriterReplaced(c *tc.C) {
+	c.Setenv("LOGGO_FORMAT", "replaced")
+	loggo.ConfigureFromEnvironment(s.context)
+	writer := &loggo.TestWriter{}
+	_, err := s.context.ReplaceWriter(loggo.DefaultWriterName, writer)
+	c.Assert(err, tc.IsNil)
+
+	loggo.RegisterFormat("replaced", func(io.Writer) loggo.Writer {
+		c.Errorf("format should not be used")
+		return nil
+	})
+	c.Check(s.context.Writer(loggo.DefaultWriterName), tc.Equals, loggo.Writer(writer))
+}
+
+// captureStderr returns what is written to os.Stderr while f is called.
+func captureStderr(c *tc.C, f func()) string {
+	reader, writer, err := os.Pipe()
+	c.Assert(err, tc.IsNil)
+	stderr := os.Stderr
+	os.Stderr = writer
+	defer func() { os.Stderr = stderr }()
+
+	f()
+	c.Assert(writer.Close(), tc.IsNil)
+	data, err := io.ReadAll(reader)
+	c.Assert(err, tc.IsNil)
+	return string(data)
+}
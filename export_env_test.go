@@ -0,0 +1,33 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build !loggo_noenv
+
+package loggo
+
+import "maps"
+
+// SaveFormats returns a function that restores the registered formats, and
+// any format the default writer of a context is waiting for, to their
+// current state.
+func SaveFormats() func() {
+	formatsMutex.Lock()
+	savedFormats := maps.Clone(formats)
+	formatsMutex.Unlock()
+
+	pendingFormat.Lock()
+	name, context, output, file, writer := pendingFormat.name, pendingFormat.context,
+		pendingFormat.output, pendingFormat.file, pendingFormat.writer
+	pendingFormat.Unlock()
+
+	return func() {
+		formatsMutex.Lock()
+		formats = savedFormats
+		formatsMutex.Unlock()
+
+		pendingFormat.Lock()
+		pendingFormat.name, pendingFormat.context = name, context
+		pendingFormat.output, pendingFormat.file, pendingFormat.writer = output, file, writer
+		pendingFormat.Unlock()
+	}
+}
@@ -3,18 +3,31 @@
 
 package loggo
 
-// WriterNames returns the names of the context's writers for testing purposes.
-func (c *Context) WriterNames() []string {
-	c.writersMutex.Lock()
-	defer c.writersMutex.Unlock()
-	var result []string
-	for name := range c.writers {
-		result = append(result, name)
-	}
-	return result
-}
+import "time"
 
 func ResetDefaultContext() {
 	ResetLogging()
 	_ = DefaultContext().AddWriter(DefaultWriterName, defaultWriter())
 }
+
+// SetConfigPollInterval sets how often WatchConfigFile polls, returning a
+// function that restores the previous interval.
+func SetConfigPollInterval(interval time.Duration) func() {
+	previous := configPollInterval
+	configPollInterval = interval
+	return func() {
+		configPollInterval = previous
+	}
+}
+
+var ConfigureFromEnvironment = configureFromEnvironment
+
+// SetDefaultFormatterTimeFormat sets the time format used by DefaultFormatter,
+// returning a function that restores the previous format.
+func SetDefaultFormatterTimeFormat(format string) func() {
+	previous := defaultFormatterTimeFormat
+	defaultFormatterTimeFormat = format
+	return func() {
+		defaultFormatterTimeFormat = previous
+	}
+}
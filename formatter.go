@@ -4,9 +4,16 @@
 package loggo
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/loggo/v3/attrs"
@@ -14,60 +21,205 @@ import (
 
 // DefaultFormatter returns the parameters separated by spaces except for
 // filename and line which are separated by a colon.  The timestamp is shown
-// to second resolution in UTC. For example:
+// to second resolution in UTC, unless the environment variable
+// LOGGO_TIME_FORMAT sets another format. For example:
 //
 //	2016-07-02 15:04:05
+//
+// The stack trace of the entry, if any, follows on the lines below, with each
+// function indented by a tab and its location by two.
 func DefaultFormatter(entry Entry) string {
-	ts := entry.Timestamp.In(time.UTC).Format("2006-01-02 15:04:05")
+	ts := entry.Timestamp.In(time.UTC).Format(defaultFormatterTimeFormat)
 	// Just get the basename from the filename
 	filename := filepath.Base(entry.Filename)
 
-	var (
-		format string
-		values []any
-	)
-	for _, attr := range entry.Attrs {
-		switch a := attr.(type) {
-		case attrs.AttrValue[string]:
-			format += " %s=%s"
-			values = append(values, a.Key(), a.Value())
-		case attrs.AttrValue[int]:
-			format += " %s=%d"
-			values = append(values, a.Key(), a.Value())
-		case attrs.AttrValue[int64]:
-			format += " %s=%d"
-			values = append(values, a.Key(), a.Value())
-		case attrs.AttrValue[uint64]:
-			format += " %s=%d"
-			values = append(values, a.Key(), a.Value())
-		case attrs.AttrValue[float64]:
-			format += " %s=%f"
-			values = append(values, a.Key(), a.Value())
-		case attrs.AttrValue[bool]:
-			format += " %s=%t"
-			values = append(values, a.Key(), a.Value())
-		case attrs.AttrValue[time.Time]:
-			format += " %s=%v"
-			values = append(values, a.Key(), a.Value())
-		case attrs.AttrValue[time.Duration]:
-			format += " %s=%v"
-			values = append(values, a.Key(), a.Value())
-		case attrs.AttrValue[any]:
-			format += " %s=%v"
-			values = append(values, a.Key(), a.Value())
-		}
-	}
+	var buf [4]attrs.Attr
+	format, values := appendAttrs("", nil, "", entry.normalizedAttrs(buf[:0]))
 
 	args := []any{ts, entry.Level, entry.Module, filename, entry.Line, entry.Message}
 	args = append(args, values...)
 
+	for _, frame := range entry.Stack {
+		format += "\n\t%s\n\t\t%s:%d"
+		args = append(args, frame.Function, frame.File, frame.Line)
+	}
+
 	return fmt.Sprintf("%s %s %s %s:%d %s"+format, args...)
 }
 
-// TimeFormat is the time format used for the default writer.
+// JSONFormatter returns the entry as a JSON object on a single line. The
+// object has the time, level, module, filename, line and message of the
+// entry, followed by its labels, attrs and stack, when it has them. For
+// example:
+//
+//	{"time":"2016-07-02T15:04:05Z","level":"INFO","module":"foo","filename":"foo.go","line":42,"message":"hello","attrs":{"count":3}}
+//
+// The attrs are encoded by kind: times in RFC 3339 format, durations and
+// stringers as strings, bytes in base64, groups as nested objects and errors
+// as their ErrorDetail. Other values are encoded with encoding/json, falling
+// back to their string representation.
+func JSONFormatter(entry Entry) string {
+	var buf bytes.Buffer
+	object := jsonObject{buf: &buf}
+	object.start()
+	object.field("time", entry.Timestamp.UTC().Format(time.RFC3339Nano))
+	object.field("level", entry.Level.String())
+	object.field("module", entry.Module)
+	object.field("filename", filepath.Base(entry.Filename))
+	object.field("line", entry.Line)
+	object.field("message", entry.Message)
+	if len(entry.Labels) > 0 {
+		object.field("labels", map[string]string(entry.Labels))
+	}
+	var attrsBuf [4]attrs.Attr
+	if entryAttrs := entry.normalizedAttrs(attrsBuf[:0]); len(entryAttrs) > 0 {
+		object.key("attrs")
+		attrsObject := jsonObject{buf: &buf}
+		attrsObject.start()
+		writeJSONAttrs(&attrsObject, entryAttrs)
+		attrsObject.end()
+	}
+	if len(entry.Stack) > 0 {
+		object.field("stack", entry.Stack)
+	}
+	object.end()
+	return buf.String()
+}
+
+// jsonObject writes the fields of a JSON object in order.
+type jsonObject struct {
+	buf    *bytes.Buffer
+	fields int
+}
+
+func (o *jsonObject) start() {
+	o.buf.WriteByte('{')
+}
+
+func (o *jsonObject) end() {
+	o.buf.WriteByte('}')
+}
+
+// key writes the key of the next field, which must be followed by its value.
+func (o *jsonObject) key(key string) {
+	if o.fields > 0 {
+		o.buf.WriteByte(',')
+	}
+	o.fields++
+	writeJSON(o.buf, key)
+	o.buf.WriteByte(':')
+}
+
+func (o *jsonObject) field(key string, value any) {
+	o.key(key)
+	writeJSON(o.buf, value)
+}
+
+// writeJSONAttrs writes the attrs as fields of the object. The attrs of a
+// group are written as a nested object, unless the group has no name.
+func writeJSONAttrs(object *jsonObject, entryAttrs []attrs.Attr) {
+	for _, a := range entryAttrs {
+		if a.Value.Kind() != attrs.KindGroup {
+			object.field(a.Key, jsonValue(a.Value))
+			continue
+		}
+		if a.Key == "" {
+			writeJSONAttrs(object, a.Value.Group())
+			continue
+		}
+		object.key(a.Key)
+		group := jsonObject{buf: object.buf}
+		group.start()
+		writeJSONAttrs(&group, a.Value.Group())
+		group.end()
+	}
+}
+
+// jsonValue returns the value of an attr to encode as JSON.
+func jsonValue(v attrs.Value) any {
+	switch v.Kind() {
+	case attrs.KindFloat64:
+		// JSON has no representation of NaN and infinities.
+		if f := v.Float64(); math.IsNaN(f) || math.IsInf(f, 0) {
+			return strconv.FormatFloat(f, 'g', -1, 64)
+		}
+		return v.Float64()
+	case attrs.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case attrs.KindDuration:
+		return v.Duration().String()
+	case attrs.KindStringer:
+		if v.Stringer() == nil {
+			return nil
+		}
+		return v.Stringer().String()
+	case attrs.KindError:
+		if v.Error() == nil {
+			return nil
+		}
+		return attrs.Details(v.Error())
+	default:
+		return v.Any()
+	}
+}
+
+// writeJSON writes the value as JSON, without escaping HTML characters. A
+// value that can't be encoded is written as its string representation.
+func writeJSON(buf *bytes.Buffer, value any) {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		_ = encoder.Encode(fmt.Sprint(value))
+	}
+	// Encode terminates each value with a newline.
+	buf.Truncate(buf.Len() - 1)
+}
+
+// appendAttrs appends the format and values for the attrs to those given.
+// The keys of the attrs are prefixed with the names of the groups they are
+// nested in, separated by dots.
+func appendAttrs(format string, values []any, prefix string, entryAttrs []attrs.Attr) (string, []any) {
+	for _, a := range entryAttrs {
+		format, values = appendAttr(format, values, prefix, a)
+	}
+	return format, values
+}
+
+func appendAttr(format string, values []any, prefix string, a attrs.Attr) (string, []any) {
+	switch a.Value.Kind() {
+	case attrs.KindFloat64:
+		format += " %s=%f"
+		values = append(values, prefix+a.Key, a.Value.Float64())
+	case attrs.KindGroup:
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, member := range a.Value.Group() {
+			format, values = appendAttr(format, values, prefix, member)
+		}
+	default:
+		format += " %s=%s"
+		values = append(values, prefix+a.Key, a.Value.String())
+	}
+	return format, values
+}
+
+// TimeFormat is the time format used by the loggocolor writers.
 // This can be set with the environment variable LOGGO_TIME_FORMAT.
 var TimeFormat = initTimeFormat()
 
+// defaultFormatterTimeFormat is the time format used by DefaultFormatter.
+// It is only changed by setting LOGGO_TIME_FORMAT, as the default differs
+// from that of TimeFormat.
+var defaultFormatterTimeFormat = initDefaultFormatterTimeFormat()
+
+func initDefaultFormatterTimeFormat() string {
+	if format := os.Getenv("LOGGO_TIME_FORMAT"); format != "" {
+		return format
+	}
+	return "2006-01-02 15:04:05"
+}
+
 func initTimeFormat() string {
 	format := os.Getenv("LOGGO_TIME_FORMAT")
 	if format != "" {
@@ -75,3 +227,36 @@ func initTimeFormat() string {
 	}
 	return "15:04:05"
 }
+
+var (
+	formatsMutex sync.Mutex
+	formats      = map[string]func(io.Writer) Writer{
+		"text": func(writer io.Writer) Writer {
+			return NewSimpleWriter(writer, DefaultFormatter)
+		},
+		"json": func(writer io.Writer) Writer {
+			return NewSimpleWriter(writer, JSONFormatter)
+		},
+	}
+)
+
+// RegisterFormat registers a function that creates writers for the named
+// output format, so that the format can be selected for the default context
+// with the LOGGO_FORMAT environment variable. The text and json formats are
+// always registered, and importing loggocolor registers the color format.
+func RegisterFormat(name string, newWriter func(io.Writer) Writer) {
+	name = strings.ToLower(name)
+	formatsMutex.Lock()
+	formats[name] = newWriter
+	formatsMutex.Unlock()
+	formatRegistered(name)
+}
+
+// formatWriter returns the function that creates writers for the named
+// format, if it is registered.
+func formatWriter(name string) (func(io.Writer) Writer, bool) {
+	formatsMutex.Lock()
+	defer formatsMutex.Unlock()
+	newWriter, ok := formats[name]
+	return newWriter, ok
+}
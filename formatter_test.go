@@ -4,10 +4,16 @@
 package loggo_test
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
 	"testing"
 	"time"
 
 	"github.com/juju/loggo/v3"
+	"github.com/juju/loggo/v3/attrs"
 	"github.com/juju/tc"
 )
 
@@ -32,3 +38,155 @@ func (*formatterSuite) TestDefaultFormat(c *tc.C) {
 	formatted := loggo.DefaultFormatter(entry)
 	c.Assert(formatted, tc.Equals, "2013-05-03 10:53:24 WARNING test.module filename:42 hello world!")
 }
+
+type logValuer struct{}
+
+func (logValuer) LogValue() slog.Value {
+	return slog.StringValue("resolved")
+}
+
+func (*formatterSuite) TestDefaultFormatTimeFormat(c *tc.C) {
+	restore := loggo.SetDefaultFormatterTimeFormat(time.RFC3339)
+	defer restore()
+	entry := loggo.Entry{
+		Level:     loggo.WARNING,
+		Module:    "test.module",
+		Filename:  "some/deep/filename",
+		Line:      42,
+		Timestamp: time.Date(2013, 5, 3, 10, 53, 24, 0, time.UTC),
+		Message:   "hello world!",
+	}
+	formatted := loggo.DefaultFormatter(entry)
+	c.Assert(formatted, tc.Equals, "2013-05-03T10:53:24Z WARNING test.module filename:42 hello world!")
+}
+
+func (*formatterSuite) TestDefaultFormatResolvesValues(c *tc.C) {
+	entry := loggo.Entry{
+		Level:     loggo.INFO,
+		Module:    "test.module",
+		Filename:  "filename",
+		Line:      42,
+		Timestamp: time.Date(2013, 5, 3, 10, 53, 24, 0, time.UTC),
+		Message:   "hello",
+		Attrs: []any{
+			attrs.Any("valuer", logValuer{}),
+			attrs.Lazy("lazy", func() int { return 42 }),
+		},
+	}
+	formatted := loggo.DefaultFormatter(entry)
+	c.Assert(formatted, tc.Equals, "2013-05-03 10:53:24 INFO test.module filename:42 hello valuer=resolved lazy=42")
+}
+
+func (*formatterSuite) TestDefaultFormatGroups(c *tc.C) {
+	entry := loggo.Entry{
+		Level:     loggo.INFO,
+		Module:    "test.module",
+		Filename:  "filename",
+		Line:      42,
+		Timestamp: time.Date(2013, 5, 3, 10, 53, 24, 0, time.UTC),
+		Message:   "request",
+		Attrs: []any{
+			attrs.Group("http",
+				attrs.String("method", "GET"),
+				attrs.Group("response", attrs.Int("status", 200)),
+			),
+			attrs.Group("", attrs.Bool("inline", true)),
+		},
+	}
+	formatted := loggo.DefaultFormatter(entry)
+	c.Assert(formatted, tc.Equals, "2013-05-03 10:53:24 INFO test.module filename:42 request http.method=GET http.response.status=200 inline=true")
+}
+
+func (*formatterSuite) TestDefaultFormatError(c *tc.C) {
+	entry := loggo.Entry{
+		Level:     loggo.ERROR,
+		Module:    "test.module",
+		Filename:  "filename",
+		Line:      42,
+		Timestamp: time.Date(2013, 5, 3, 10, 53, 24, 0, time.UTC),
+		Message:   "failed",
+		Attrs:     []any{attrs.Err(fmt.Errorf("outer: %w", errors.New("inner")))},
+	}
+	formatted := loggo.DefaultFormatter(entry)
+	c.Assert(formatted, tc.Equals, "2013-05-03 10:53:24 ERROR test.module filename:42 failed error=outer: inner")
+}
+
+func (*formatterSuite) TestDefaultFormatCollections(c *tc.C) {
+	entry := loggo.Entry{
+		Level:     loggo.INFO,
+		Module:    "test.module",
+		Filename:  "filename",
+		Line:      42,
+		Timestamp: time.Date(2013, 5, 3, 10, 53, 24, 0, time.UTC),
+		Message:   "hello",
+		Attrs: []any{
+			attrs.Strings("names", []string{"a b", "c"}),
+			attrs.Ints("counts", []int{1, 2}),
+			attrs.StringMap("tags", map[string]string{"z": "1", "a": "2"}),
+			attrs.Bytes("payload", []byte{0xde, 0xad, 0xbe, 0xef}),
+			attrs.Stringer("timeout", 5*time.Second),
+		},
+	}
+	formatted := loggo.DefaultFormatter(entry)
+	c.Assert(formatted, tc.Equals, `2013-05-03 10:53:24 INFO test.module filename:42 hello names=["a b" "c"] counts=[1 2] tags=map["a":"2" "z":"1"] payload=deadbeef timeout=5s`)
+}
+
+func (*formatterSuite) TestDefaultFormatStack(c *tc.C) {
+	entry := loggo.Entry{
+		Level:     loggo.ERROR,
+		Module:    "test.module",
+		Filename:  "filename",
+		Line:      42,
+		Timestamp: time.Date(2013, 5, 3, 10, 53, 24, 0, time.UTC),
+		Message:   "failed",
+		Stack: []loggo.Frame{
+			{Function: "main.run", File: "/src/main.go", Line: 42},
+			{Function: "main.main", File: "/src/main.go", Line: 10},
+		},
+	}
+	formatted := loggo.DefaultFormatter(entry)
+	c.Assert(formatted, tc.Equals, "2013-05-03 10:53:24 ERROR test.module filename:42 failed"+
+		"\n\tmain.run\n\t\t/src/main.go:42"+
+		"\n\tmain.main\n\t\t/src/main.go:10")
+}
+
+func (*formatterSuite) TestJSONFormatter(c *tc.C) {
+	entry := loggo.Entry{
+		Level:     loggo.INFO,
+		Module:    "test.module",
+		Filename:  "/src/filename.go",
+		Line:      42,
+		Timestamp: time.Date(2013, 5, 3, 10, 53, 24, 500, time.UTC),
+		Message:   "hello <world>",
+		Labels:    loggo.Labels{"b": "2", "a": "1"},
+		Attrs: []any{
+			attrs.String("name", "value"),
+			attrs.Int("count", 3),
+			attrs.Float64("ratio", math.Inf(1)),
+			attrs.Duration("took", 1500*time.Millisecond),
+			attrs.Bytes("payload", []byte{0xde, 0xad}),
+			attrs.Group("http", attrs.String("method", "GET"), attrs.Group("", attrs.Bool("inline", true))),
+			attrs.Err(errors.New("boom")),
+			attrs.Any("func", func() {}),
+		},
+		Stack: []loggo.Frame{{Function: "main.main", File: "/src/main.go", Line: 10}},
+	}
+	formatted := loggo.JSONFormatter(entry)
+	c.Check(formatted, tc.Equals, `{"time":"2013-05-03T10:53:24.0000005Z","level":"INFO","module":"test.module","filename":"filename.go","line":42,"message":"hello <world>",`+
+		`"labels":{"a":"1","b":"2"},`+
+		`"attrs":{"name":"value","count":3,"ratio":"+Inf","took":"1.5s","payload":"3q0=","http":{"method":"GET","inline":true},"error":{"message":"boom","type":"*errors.errorString"},"func":"`+fmt.Sprint(entry.Attrs[7].(attrs.AttrValue[any]).Value())+`"},`+
+		`"stack":[{"function":"main.main","file":"/src/main.go","line":10}]}`)
+
+	var decoded map[string]any
+	c.Check(json.Unmarshal([]byte(formatted), &decoded), tc.IsNil)
+}
+
+func (*formatterSuite) TestJSONFormatterMinimal(c *tc.C) {
+	entry := loggo.Entry{
+		Level:     loggo.WARNING,
+		Timestamp: time.Date(2013, 5, 3, 10, 53, 24, 0, time.UTC),
+		Message:   "hello",
+	}
+	formatted := loggo.JSONFormatter(entry)
+	c.Check(formatted, tc.Equals, `{"time":"2013-05-03T10:53:24Z","level":"WARNING","module":"","filename":".","line":0,"message":"hello"}`)
+}
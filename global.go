@@ -15,6 +15,12 @@ func newDefaultContxt() *Context {
 	return ctx
 }
 
+func init() {
+	// The default context is configured from the environment once it has
+	// been created, as invalid values are logged through it.
+	configureFromEnvironment(defaultContext)
+}
+
 // DefaultContext returns the global default logging context.
 func DefaultContext() *Context {
 	return defaultContext
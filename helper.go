@@ -46,18 +46,77 @@ func caller(skip int) (uintptr, string, int, bool) {
 	if n == 0 {
 		return 0, "", 0, false
 	}
+	pcs := skipHelpers(pc[:n])
+	frame, ok := callerFrame(pcs[0])
+	if !ok {
+		return 0, "", 0, false
+	}
+	return frame.pc, frame.file, frame.line, true
+}
+
+// maxStackDepth is the maximum number of frames captured by callerStack.
+const maxStackDepth = 64
+
+// callerStack returns the stack trace of the caller, starting at the frame
+// reported by caller.
+func callerStack(skip int) []Frame {
+	pc := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip+2, pc)
+	if n == 0 {
+		return nil
+	}
+	frames := runtime.CallersFrames(skipHelpers(pc[:n]))
+	var stack []Frame
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, Frame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+		})
+		if !more {
+			return stack
+		}
+	}
+}
+
+// skipHelpers returns the program counters from the outermost call of a
+// function marked by helper, or all of them if there is none.
+func skipHelpers(pcs []uintptr) []uintptr {
 	helperMutex.RLock()
-	pcs := pc[:]
-	for i := n - 1; i >= 0; i-- {
-		if _, ok := helpers[pc[i]]; ok {
-			pcs = pc[i:]
-			break
+	defer helperMutex.RUnlock()
+	for i := len(pcs) - 1; i >= 0; i-- {
+		if _, ok := helpers[pcs[i]]; ok {
+			return pcs[i:]
 		}
 	}
-	helperMutex.RUnlock()
-	frames := runtime.CallersFrames(pcs)
-	if frame, ok := frames.Next(); ok {
-		return frame.PC, frame.File, frame.Line, true
+	return pcs
+}
+
+// frames caches the frames returned by callerFrame by program counter. It is
+// a sync.Map, as it is read by every logging call but only written the first
+// time each call site logs.
+var frames sync.Map
+
+type frame struct {
+	pc   uintptr
+	file string
+	line int
+}
+
+// callerFrame returns the location of the call for the given return program
+// counter. The locations are cached, as there are only so many call sites and
+// runtime.CallersFrames allocates each time it is called.
+func callerFrame(pc uintptr) (frame, bool) {
+	if f, ok := frames.Load(pc); ok {
+		return f.(frame), true
+	}
+
+	next, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if next.PC == 0 {
+		return frame{}, false
 	}
-	return 0, "", 0, false
+	f := frame{pc: next.PC, file: next.File, line: next.Line}
+	frames.Store(pc, f)
+	return f, true
 }
@@ -4,6 +4,8 @@
 package loggo
 
 import (
+	"encoding/json"
+	"fmt"
 	"strings"
 	"sync/atomic"
 )
@@ -69,6 +71,64 @@ func (level Level) String() string {
 	}
 }
 
+// MarshalText implements encoding.TextMarshaler, so that levels can be
+// encoded by name, including by YAML libraries that use TextMarshaler.
+func (level Level) MarshalText() ([]byte, error) {
+	if level > CRITICAL {
+		return nil, fmt.Errorf("unknown severity level %d", uint32(level))
+	}
+	return []byte(level.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing the level with
+// ParseLevel.
+func (level *Level) UnmarshalText(text []byte) error {
+	str := strings.TrimSpace(string(text))
+	parsed, ok := ParseLevel(str)
+	if !ok {
+		return fmt.Errorf("unknown severity level %q", str)
+	}
+	*level = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding the level as a string.
+func (level Level) MarshalJSON() ([]byte, error) {
+	text, err := level.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding the level from a
+// string. Levels encoded as numbers by earlier versions are also accepted.
+// A JSON null leaves the level unchanged.
+func (level *Level) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		return level.UnmarshalText([]byte(str))
+	}
+	var number uint32
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("severity level should be a string or a number, found %s", data)
+	}
+	if Level(number) > CRITICAL {
+		return fmt.Errorf("unknown severity level %d", number)
+	}
+	*level = Level(number)
+	return nil
+}
+
+// Set implements flag.Value, so that a level can be set from the command
+// line with flag.Var.
+func (level *Level) Set(value string) error {
+	return level.UnmarshalText([]byte(value))
+}
+
 // Short returns a five character string to use in
 // aligned logging output.
 func (level Level) Short() string {
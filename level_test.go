@@ -4,6 +4,9 @@
 package loggo_test
 
 import (
+	"encoding/json"
+	"flag"
+	"io"
 	"testing"
 
 	"github.com/juju/loggo/v3"
@@ -97,3 +100,80 @@ func (s *LevelSuite) TestLevelStringValue(c *tc.C) {
 		c.Assert(level.String(), tc.Equals, str)
 	}
 }
+
+func (s *LevelSuite) TestLevelText(c *tc.C) {
+	for _, test := range parseLevelTests {
+		var level loggo.Level
+		err := level.UnmarshalText([]byte(test.str))
+		if test.fail {
+			c.Check(err, tc.ErrorMatches, `unknown severity level ".*"`)
+			continue
+		}
+		c.Assert(err, tc.IsNil)
+		c.Check(level, tc.Equals, test.level)
+
+		text, err := level.MarshalText()
+		c.Assert(err, tc.IsNil)
+		c.Check(string(text), tc.Equals, test.level.String())
+	}
+
+	_, err := loggo.Level(42).MarshalText()
+	c.Check(err, tc.ErrorMatches, `unknown severity level 42`)
+}
+
+func (s *LevelSuite) TestLevelJSON(c *tc.C) {
+	var config struct {
+		Level loggo.Level `json:"level"`
+	}
+	err := json.Unmarshal([]byte(`{"level": "debug"}`), &config)
+	c.Assert(err, tc.IsNil)
+	c.Check(config.Level, tc.Equals, loggo.DEBUG)
+
+	data, err := json.Marshal(config)
+	c.Assert(err, tc.IsNil)
+	c.Check(string(data), tc.Equals, `{"level":"DEBUG"}`)
+
+	err = json.Unmarshal([]byte(`{"level": "loud"}`), &config)
+	c.Check(err, tc.ErrorMatches, `unknown severity level "loud"`)
+	err = json.Unmarshal([]byte(`{"level": null}`), &config)
+	c.Assert(err, tc.IsNil)
+	c.Check(config.Level, tc.Equals, loggo.DEBUG)
+
+	err = json.Unmarshal([]byte(`{"level": true}`), &config)
+	c.Check(err, tc.ErrorMatches, `severity level should be a string or a number, found true`)
+}
+
+func (s *LevelSuite) TestLevelJSONNumber(c *tc.C) {
+	// Earlier versions encoded levels as numbers.
+	var config struct {
+		Level loggo.Level `json:"level"`
+	}
+	err := json.Unmarshal([]byte(`{"level": 2}`), &config)
+	c.Assert(err, tc.IsNil)
+	c.Check(config.Level, tc.Equals, loggo.DEBUG)
+
+	err = json.Unmarshal([]byte(`{"level": 0}`), &config)
+	c.Assert(err, tc.IsNil)
+	c.Check(config.Level, tc.Equals, loggo.UNSPECIFIED)
+
+	err = json.Unmarshal([]byte(`{"level": 7}`), &config)
+	c.Check(err, tc.ErrorMatches, `unknown severity level 7`)
+	err = json.Unmarshal([]byte(`{"level": -1}`), &config)
+	c.Check(err, tc.ErrorMatches, `severity level should be a string or a number, found -1`)
+	err = json.Unmarshal([]byte(`{"level": 2.5}`), &config)
+	c.Check(err, tc.ErrorMatches, `severity level should be a string or a number, found 2.5`)
+}
+
+func (s *LevelSuite) TestLevelFlag(c *tc.C) {
+	level := loggo.WARNING
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.Var(&level, "level", "the logging level")
+
+	err := flags.Parse([]string{"-level", "trace"})
+	c.Assert(err, tc.IsNil)
+	c.Check(level, tc.Equals, loggo.TRACE)
+
+	err = flags.Parse([]string{"-level", "loud"})
+	c.Check(err, tc.ErrorMatches, `invalid value "loud" for flag -level: unknown severity level "loud"`)
+}
@@ -29,6 +29,7 @@ const (
 type Logger struct {
 	impl   *module
 	labels Labels
+	attrs  []any
 
 	// CallDepth is the number of stack frames to ascend to find the caller.
 	callDepth int
@@ -52,6 +53,27 @@ func (logger Logger) WithLabels(labels Labels) Logger {
 	return result
 }
 
+// With returns a logger whose module is the same as this logger and the
+// returned logger will add the specified attrs to each log entry, after any
+// attrs it already adds. The values are the same as the args of the
+// structured logging methods: typed attrs, created with the attrs package,
+// and alternating key/value pairs, converted as attrs.Args does.
+// With only targets a specific logger with attrs. Children of the logger
+// will not inherit the attrs.
+// To add attrs to all child loggers, use ChildWithAttrs.
+func (logger Logger) With(values ...any) Logger {
+	typed := attrs.Args(values...)
+	if len(typed) == 0 {
+		return logger
+	}
+
+	result := logger
+	result.attrs = make([]any, 0, len(logger.attrs)+len(typed))
+	result.attrs = append(result.attrs, logger.attrs...)
+	result.attrs = append(result.attrs, typed...)
+	return result
+}
+
 // WithCallDepth returns a logger whose call depth is set to the specified
 // value.
 func (logger Logger) WithCallDepth(callDepth int) Logger {
@@ -139,6 +161,33 @@ func (logger Logger) ChildWithLabels(name string, labels Labels) Logger {
 	return result
 }
 
+// ChildWithAttrs returns the Logger whose module name is the composed of this
+// Logger's name and the specified name with the specified attrs added to each
+// log entry. The values are converted in the same way as for With.
+// Adding attrs to the child logger will cause all child loggers to also
+// inherit the attrs of the parent(s) loggers.
+// For targeting a singular logger with attrs, use With which are not
+// inherited by child loggers.
+func (logger Logger) ChildWithAttrs(name string, values ...any) Logger {
+	module := logger.getModule()
+	path := module.name
+	if path == "" {
+		path = name
+	} else {
+		path += "." + name
+	}
+
+	typed := attrs.Args(values...)
+	parentAttrs := module.getAttrs()
+	merged := make([]any, 0, len(parentAttrs)+len(typed))
+	merged = append(merged, parentAttrs...)
+	merged = append(merged, typed...)
+
+	result := module.context.GetLogger(path)
+	result.impl.setAttrs(merged)
+	return result
+}
+
 // Name returns the logger's module name.
 func (logger Logger) Name() string {
 	return logger.getModule().Name()
@@ -170,7 +219,8 @@ func (logger Logger) EffectiveLogLevel() Level {
 // See EffectiveLogLevel for how this affects the
 // actual messages logged.
 func (logger Logger) SetLogLevel(level Level) {
-	logger.getModule().setLevel(level)
+	module := logger.getModule()
+	module.context.setLevel(module, level)
 }
 
 // Logf logs a printf-formatted message at the given level.
@@ -183,7 +233,7 @@ func (logger Logger) Logf(ctx context.Context, level Level, message string, args
 }
 
 func (logger Logger) logf(ctx context.Context, level Level, message string, args ...interface{}) error {
-	return logger.logCallf(ctx, logger.callDepth, level, message, nil, args...)
+	return logger.logCall(ctx, logger.callDepth, level, message, nil, true, args)
 }
 
 // LogWithlabelsf logs a printf-formatted message at the given level with extra
@@ -198,7 +248,7 @@ func (logger Logger) LogWithLabelsf(
 	extraLabels map[string]string,
 	args ...interface{},
 ) error {
-	return logger.logCallf(ctx, logger.callDepth-1, level, message, extraLabels, args...)
+	return logger.logCall(ctx, logger.callDepth-1, level, message, extraLabels, true, args)
 }
 
 // LogCallf logs a printf-formatted message at the given level.
@@ -215,23 +265,29 @@ func (logger Logger) LogCallf(
 	message string,
 	args ...interface{},
 ) error {
-	return logger.logCallf(ctx, calldepth, level, message, nil, args...)
+	return logger.logCall(ctx, calldepth, level, message, nil, true, args)
 }
 
-// logCallf is a private method for logging a printf-formatted message at the
-// given level. Used by LogWithLabelsf and LogCallf.
-func (logger Logger) logCallf(
+// logCall is a private method for logging a message at the given level. If
+// printf is true, the message is formatted with the args that aren't typed
+// attrs, otherwise the args are typed attrs and key/value pairs and the
+// message is used as it is. Used by all the logging methods.
+func (logger Logger) logCall(
 	ctx context.Context,
 	calldepth int,
 	level Level,
 	message string,
 	extraLabels map[string]string,
-	args ...interface{},
+	printf bool,
+	args []any,
 ) error {
 	module := logger.getModule()
 	if !module.willWrite(level) {
 		return nil
 	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	// Gather time, and filename, line number.
 	now := time.Now() // get this early.
 	// Param to Caller is the call depth.  Since this method is called from
@@ -241,20 +297,37 @@ func (logger Logger) logCallf(
 		file = "???"
 		line = 0
 	}
+	var stack []Frame
+	if stackLevel := module.context.stackTraceLevel.get(); stackLevel != UNSPECIFIED && level >= stackLevel {
+		stack = callerStack(calldepth + 1)
+	}
 	// Trim newline off format string, following usual
 	// Go logging conventions.
 	if len(message) > 0 && message[len(message)-1] == '\n' {
 		message = message[0 : len(message)-1]
 	}
 
-	// To avoid having a proliferation of Info/Infof methods,
-	// only use Sprintf if there are any args, and rely on the
-	// `go vet` tool for the obvious cases where someone has forgotten
-	// to provide an arg.
+	// For the printf-style methods, only use Sprintf if there are
+	// any args, and rely on the `go vet` tool for the obvious cases
+	// where someone has forgotten to provide an arg.
 	formattedMessage := message
-	messageArgs, attrs := attrs.Attrs(args...)
-	if len(messageArgs) > 0 {
-		formattedMessage = fmt.Sprintf(message, messageArgs...)
+	var callAttrs []any
+	if printf {
+		var messageArgs []any
+		messageArgs, callAttrs = attrs.Attrs(args...)
+		if len(messageArgs) > 0 {
+			formattedMessage = fmt.Sprintf(message, messageArgs...)
+		}
+	} else {
+		callAttrs = attrs.Args(args...)
+	}
+	// The attrs of the module and the logger come before those of the call.
+	entryAttrs := callAttrs
+	if moduleAttrs := module.getAttrs(); len(moduleAttrs) > 0 || len(logger.attrs) > 0 {
+		entryAttrs = make([]any, 0, len(moduleAttrs)+len(logger.attrs)+len(callAttrs))
+		entryAttrs = append(entryAttrs, moduleAttrs...)
+		entryAttrs = append(entryAttrs, logger.attrs...)
+		entryAttrs = append(entryAttrs, callAttrs...)
 	}
 
 	entry := Entry{
@@ -264,19 +337,24 @@ func (logger Logger) logCallf(
 		Timestamp: now,
 		Message:   formattedMessage,
 		PC:        pc,
-		Attrs:     attrs,
+		Attrs:     entryAttrs,
+		Stack:     stack,
 	}
-	entry.Labels = make(Labels)
-	if len(module.tags) > 0 {
-		entry.Labels[LoggerTags] = strings.Join(module.tags, ",")
+	// The labels are left nil when there are none, so that logging without
+	// labels doesn't allocate.
+	if len(module.tags) > 0 || len(module.labels) > 0 || len(logger.labels) > 0 || len(extraLabels) > 0 {
+		entry.Labels = make(Labels)
+		if len(module.tags) > 0 {
+			entry.Labels[LoggerTags] = strings.Join(module.tags, ",")
+		}
+		for k, v := range module.labels {
+			entry.Labels[k] = v
+		}
+		maps.Copy(entry.Labels, logger.labels)
+		// Add extra labels if there's any given.
+		maps.Copy(entry.Labels, extraLabels)
 	}
-	for k, v := range module.labels {
-		entry.Labels[k] = v
-	}
-	maps.Copy(entry.Labels, logger.labels)
-	// Add extra labels if there's any given.
-	maps.Copy(entry.Labels, extraLabels)
-	return module.write(context.Background(), entry)
+	return module.write(ctx, entry)
 }
 
 // Criticalf logs the printf-formatted message at critical level.
@@ -309,6 +387,52 @@ func (logger Logger) Tracef(ctx context.Context, message string, args ...interfa
 	return logger.logf(ctx, TRACE, message, args...)
 }
 
+// Log logs the message at the given level, without formatting it. The args
+// are typed attrs, created with the attrs package, or alternating key/value
+// pairs, which are converted to typed attrs. A value that isn't a typed attr
+// and isn't preceded by a string key is added with the key attrs.BadKey.
+// A message will be discarded if level is less than the
+// the effective log level of the logger.
+// Note that the writers may also filter out messages that
+// are less than their registered minimum severity level.
+func (logger Logger) Log(ctx context.Context, level Level, message string, args ...any) error {
+	return logger.log(ctx, level, message, args...)
+}
+
+func (logger Logger) log(ctx context.Context, level Level, message string, args ...any) error {
+	return logger.logCall(ctx, logger.callDepth, level, message, nil, false, args)
+}
+
+// Critical logs the message at critical level. See Log for the args.
+func (logger Logger) Critical(ctx context.Context, message string, args ...any) error {
+	return logger.log(ctx, CRITICAL, message, args...)
+}
+
+// Error logs the message at error level. See Log for the args.
+func (logger Logger) Error(ctx context.Context, message string, args ...any) error {
+	return logger.log(ctx, ERROR, message, args...)
+}
+
+// Warning logs the message at warning level. See Log for the args.
+func (logger Logger) Warning(ctx context.Context, message string, args ...any) error {
+	return logger.log(ctx, WARNING, message, args...)
+}
+
+// Info logs the message at info level. See Log for the args.
+func (logger Logger) Info(ctx context.Context, message string, args ...any) error {
+	return logger.log(ctx, INFO, message, args...)
+}
+
+// Debug logs the message at debug level. See Log for the args.
+func (logger Logger) Debug(ctx context.Context, message string, args ...any) error {
+	return logger.log(ctx, DEBUG, message, args...)
+}
+
+// Trace logs the message at trace level. See Log for the args.
+func (logger Logger) Trace(ctx context.Context, message string, args ...any) error {
+	return logger.log(ctx, TRACE, message, args...)
+}
+
 // IsLevelEnabled returns whether debugging is enabled
 // for the given log level.
 func (logger Logger) IsLevelEnabled(level Level) bool {
@@ -4,6 +4,7 @@
 package loggo_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -166,6 +167,91 @@ func (s *LoggerSuite) TestInheritedLabels(c *tc.C) {
 	})
 }
 
+// attrKeys returns the keys of the typed attrs of the entry.
+func attrKeys(entry loggo.Entry) []string {
+	var keys []string
+	for _, attr := range entry.Attrs {
+		keys = append(keys, attr.(interface{ Key() string }).Key())
+	}
+	return keys
+}
+
+func (s *LoggerSuite) TestWith(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	context := loggo.NewContext(loggo.INFO)
+	err := context.AddWriter("test", writer)
+	c.Assert(err, tc.IsNil)
+
+	logger := context.GetLogger("testing")
+	loggerWithAttrs := logger.With(attrs.String("foo", "bar"), "id", 42)
+	loggerWithMoreAttrs := loggerWithAttrs.With(attrs.Int("count", 1))
+
+	_ = logger.Logf(c.Context(), loggo.INFO, "without attrs")
+	_ = loggerWithAttrs.Logf(c.Context(), loggo.INFO, "with attrs", attrs.Bool("call", true))
+	_ = loggerWithMoreAttrs.Logf(c.Context(), loggo.INFO, "with more attrs")
+	_ = loggerWithAttrs.Child("child").Logf(c.Context(), loggo.INFO, "child")
+
+	logs := writer.Log()
+	c.Assert(logs, tc.HasLen, 4)
+	c.Check(logs[0].Attrs, tc.HasLen, 0)
+	c.Check(attrKeys(logs[1]), tc.DeepEquals, []string{"foo", "id", "call"})
+	c.Check(logs[1].Attrs[1].(attrs.AttrValue[int]).Value(), tc.Equals, 42)
+	c.Check(attrKeys(logs[2]), tc.DeepEquals, []string{"foo", "id", "count"})
+	c.Check(logs[3].Attrs, tc.HasLen, 0)
+}
+
+func (s *LoggerSuite) TestInheritedAttrs(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	context := loggo.NewContext(loggo.INFO)
+	err := context.AddWriter("test", writer)
+	c.Assert(err, tc.IsNil)
+
+	logger := context.GetLogger("testing")
+	nested := logger.ChildWithAttrs("nested", attrs.String("foo", "bar"))
+	deepNested := nested.
+		ChildWithAttrs("deepnested", "fred", "tim").
+		Child("deeper")
+	scoped := nested.With(attrs.String("hello", "world"))
+
+	_ = logger.Logf(c.Context(), loggo.INFO, "without attrs")
+	_ = nested.Logf(c.Context(), loggo.INFO, "nested")
+	_ = deepNested.Logf(c.Context(), loggo.INFO, "deep nested", attrs.Int("call", 1))
+	_ = scoped.Logf(c.Context(), loggo.INFO, "scoped")
+
+	logs := writer.Log()
+	c.Assert(logs, tc.HasLen, 4)
+	c.Check(logs[0].Attrs, tc.HasLen, 0)
+	c.Check(attrKeys(logs[1]), tc.DeepEquals, []string{"foo"})
+	c.Check(attrKeys(logs[2]), tc.DeepEquals, []string{"foo", "fred", "call"})
+	c.Check(attrKeys(logs[3]), tc.DeepEquals, []string{"foo", "hello"})
+}
+
+func (s *LoggerSuite) TestChildWithAttrsWhileLogging(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	context := loggo.NewContext(loggo.INFO)
+	err := context.AddWriter("test", writer)
+	c.Assert(err, tc.IsNil)
+
+	logger := context.GetLogger("testing")
+	child := logger.Child("child")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			_ = child.Infof(c.Context(), "message %d", i)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		logger.ChildWithAttrs("child", "count", i)
+	}
+	<-done
+
+	c.Check(writer.Log(), tc.HasLen, 100)
+	_ = child.Infof(c.Context(), "last")
+	logs := writer.Log()
+	c.Check(attrKeys(logs[100]), tc.DeepEquals, []string{"count"})
+}
+
 func (s *LoggerSuite) TestLogWithStaticAndDynamicLabels(c *tc.C) {
 	writer := &loggo.TestWriter{}
 	context := loggo.NewContext(loggo.INFO)
@@ -529,3 +615,76 @@ func (s *LoggerSuite) TestAttrsInterleavedWithFormatArgs(c *tc.C) {
 	c.Check(logs[0].Attrs[1].(attrs.AttrValue[int]).Key(), tc.Equals, "middle")
 	c.Check(logs[0].Attrs[2].(attrs.AttrValue[bool]).Key(), tc.Equals, "after")
 }
+
+func (s *LoggerSuite) TestLazyAttrsOnlyEvaluatedWhenWritten(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	context := loggo.NewContext(loggo.DEBUG)
+	err := context.AddWriter("test", loggo.NewMinimumLevelWriter(writer, loggo.INFO))
+	c.Assert(err, tc.IsNil)
+
+	logger := context.GetLogger("testing")
+	calls := 0
+	lazy := func() any {
+		return attrs.Lazy("dump", func() string {
+			calls++
+			return "expensive"
+		})
+	}
+
+	// Filtered out by the module level and by the writer level.
+	_ = logger.Tracef(c.Context(), "trace", lazy())
+	_ = logger.Debugf(c.Context(), "debug", lazy())
+	c.Check(calls, tc.Equals, 0)
+
+	_ = logger.Infof(c.Context(), "info", lazy())
+	c.Check(calls, tc.Equals, 0)
+	logs := writer.Log()
+	c.Assert(logs, tc.HasLen, 1)
+	c.Check(logs[0].Attrs[0].(attrs.AttrValue[any]).Value(), tc.Equals, "expensive")
+	c.Check(calls, tc.Equals, 1)
+}
+
+func (s *LoggerSuite) TestStructuredMethods(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	context := loggo.NewContext(loggo.TRACE)
+	err := context.AddWriter("test", writer)
+	c.Assert(err, tc.IsNil)
+
+	logger := context.GetLogger("testing").With(attrs.String("scope", "test"))
+	_ = logger.Critical(c.Context(), "critical")
+	_ = logger.Error(c.Context(), "error")
+	_ = logger.Warning(c.Context(), "warning")
+	_ = logger.Info(c.Context(), "info")
+	_ = logger.Debug(c.Context(), "debug")
+	_ = logger.Trace(c.Context(), "trace")
+	_ = logger.Log(c.Context(), loggo.UNSPECIFIED, "dropped")
+
+	logs := writer.Log()
+	c.Assert(logs, tc.HasLen, 6)
+	levels := []loggo.Level{loggo.CRITICAL, loggo.ERROR, loggo.WARNING, loggo.INFO, loggo.DEBUG, loggo.TRACE}
+	for i, level := range levels {
+		c.Check(logs[i].Level, tc.Equals, level)
+		c.Check(logs[i].Message, tc.Equals, strings.ToLower(level.String()))
+		c.Check(attrKeys(logs[i]), tc.DeepEquals, []string{"scope"})
+	}
+}
+
+func (s *LoggerSuite) TestStructuredArgs(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	context := loggo.NewContext(loggo.INFO)
+	err := context.AddWriter("test", writer)
+	c.Assert(err, tc.IsNil)
+
+	logger := context.GetLogger("testing")
+	_ = logger.Info(c.Context(), "100% done %s\n", "user", "bob", attrs.Int("count", 2), "retries", 3, "dangling")
+
+	logs := writer.Log()
+	c.Assert(logs, tc.HasLen, 1)
+	c.Check(logs[0].Message, tc.Equals, "100% done %s")
+	c.Check(logs[0].Attrs, tc.DeepEquals, []any{
+		attrs.String("user", "bob"),
+		attrs.Int("count", 2),
+		attrs.Int("retries", 3),
+		attrs.String(attrs.BadKey, "dangling"),
+	})
+}
@@ -4,6 +4,7 @@
 package loggo_test
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -75,6 +76,9 @@ func (s *LoggingSuite) TestLocationCapture(c *tc.C) {
 	_ = s.logger.Logf(c.Context(), loggo.INFO, "logf msg")                          //tag logf-location
 	_ = s.logger.LogCallf(c.Context(), 1, loggo.INFO, "logcallf msg")               //tag logcallf-location
 	_ = s.logger.LogWithLabelsf(c.Context(), loggo.INFO, "logwithlabelsf msg", nil) //tag logwithlabelsf-location
+	_ = s.logger.Info(c.Context(), "info msg", "key", "value")                      //tag info-structured-location
+	_ = s.logger.Log(c.Context(), loggo.INFO, "log msg")                            //tag log-location
+	s.helperInfo(c, "helper msg")                                                   //tag helper-structured-location
 
 	log := s.writer.Log()
 	tags := []string{
@@ -88,6 +92,9 @@ func (s *LoggingSuite) TestLocationCapture(c *tc.C) {
 		"logf-location",
 		"logcallf-location",
 		"logwithlabelsf-location",
+		"info-structured-location",
+		"log-location",
+		"helper-structured-location",
 	}
 	c.Assert(log, tc.HasLen, len(tags))
 	for x := range tags {
@@ -95,11 +102,39 @@ func (s *LoggingSuite) TestLocationCapture(c *tc.C) {
 	}
 }
 
+func (s *LoggingSuite) TestStackTrace(c *tc.C) {
+	s.context.SetStackTraceLevel(loggo.INFO)
+	c.Assert(s.context.StackTraceLevel(), tc.Equals, loggo.INFO)
+
+	_ = s.logger.Debugf(c.Context(), "debug message")
+	_ = s.logger.Infof(c.Context(), "info message") //tag info-stack-location
+	s.helperInfof(c, "helper message")              //tag helper-stack-location
+	s.context.SetStackTraceLevel(loggo.UNSPECIFIED)
+	_ = s.logger.Errorf(c.Context(), "error message")
+
+	log := s.writer.Log()
+	c.Assert(log, tc.HasLen, 4)
+	c.Check(log[0].Stack, tc.HasLen, 0)
+	c.Check(log[3].Stack, tc.HasLen, 0)
+	for i, tag := range []string{"info-stack-location", "helper-stack-location"} {
+		entry := log[i+1]
+		c.Assert(len(entry.Stack) > 1, tc.IsTrue)
+		frame := entry.Stack[0]
+		assertLocation(c, loggo.Entry{Filename: filepath.Base(frame.File), Line: frame.Line}, tag)
+		c.Check(frame.Function, tc.Matches, `.*\.TestStackTrace`)
+	}
+}
+
 func (s *LoggingSuite) helperInfof(c *tc.C, format string, args ...any) {
 	s.logger.Helper()
 	_ = s.logger.Infof(c.Context(), format, args...)
 }
 
+func (s *LoggingSuite) helperInfo(c *tc.C, message string, args ...any) {
+	s.logger.Helper()
+	_ = s.logger.Info(c.Context(), message, args...)
+}
+
 func (s *LoggingSuite) TestLogDoesntLogWeirdLevels(c *tc.C) {
 	_ = s.logger.Logf(c.Context(), loggo.UNSPECIFIED, "message")
 	c.Assert(s.writer.Log(), tc.HasLen, 0)
@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
-	"time"
+	"strings"
 
 	"github.com/juju/ansiterm"
 	"github.com/juju/loggo/v3"
@@ -29,6 +29,12 @@ var (
 	LocationColor = ansiterm.Foreground(ansiterm.BrightBlue)
 )
 
+func init() {
+	// Allow the default context to use the color writer, with
+	// LOGGO_FORMAT=color.
+	loggo.RegisterFormat("color", NewWriter)
+}
+
 type colorWriter struct {
 	writer *ansiterm.Writer
 }
@@ -66,45 +72,93 @@ func (w *colorWriter) Write(_ context.Context, entry loggo.Entry) error {
 		return err
 	}
 
-	for _, attr := range entry.Attrs {
-		switch a := attr.(type) {
-		case attrs.AttrValue[string]:
-			if _, err := fmt.Fprintf(w.writer, "  %s=%s\n", a.Key(), a.Value()); err != nil {
-				return err
-			}
-		case attrs.AttrValue[int]:
-			if _, err := fmt.Fprintf(w.writer, "  %s=%d\n", a.Key(), a.Value()); err != nil {
-				return err
-			}
-		case attrs.AttrValue[int64]:
-			if _, err := fmt.Fprintf(w.writer, "  %s=%d\n", a.Key(), a.Value()); err != nil {
-				return err
-			}
-		case attrs.AttrValue[uint64]:
-			if _, err := fmt.Fprintf(w.writer, "  %s=%d\n", a.Key(), a.Value()); err != nil {
-				return err
-			}
-		case attrs.AttrValue[float64]:
-			if _, err := fmt.Fprintf(w.writer, "  %s=%f\n", a.Key(), a.Value()); err != nil {
-				return err
-			}
-		case attrs.AttrValue[bool]:
-			if _, err := fmt.Fprintf(w.writer, "  %s=%t\n", a.Key(), a.Value()); err != nil {
-				return err
-			}
-		case attrs.AttrValue[time.Time]:
-			if _, err := fmt.Fprintf(w.writer, "  %s=%v\n", a.Key(), a.Value()); err != nil {
-				return err
-			}
-		case attrs.AttrValue[time.Duration]:
-			if _, err := fmt.Fprintf(w.writer, "  %s=%v\n", a.Key(), a.Value()); err != nil {
+	if err := w.writeAttrs("  ", entry.NormalizedAttrs()); err != nil {
+		return err
+	}
+	return w.writeStack("  ", entry.Stack)
+}
+
+// writeStack writes each frame of the stack trace with the given indent,
+// with the location of the frame indented further below its function.
+func (w *colorWriter) writeStack(indent string, stack []loggo.Frame) error {
+	for _, frame := range stack {
+		if _, err := fmt.Fprintf(w.writer, "%s%s\n", indent, frame.Function); err != nil {
+			return err
+		}
+		LocationColor.Fprintf(w.writer, "%s  %s:%d\n", indent, frame.File, frame.Line)
+	}
+	return nil
+}
+
+// writeAttrs writes each of the attrs on its own line with the given
+// indent. The attrs of a group are written below its name, indented further.
+func (w *colorWriter) writeAttrs(indent string, entryAttrs []attrs.Attr) error {
+	for _, a := range entryAttrs {
+		if err := w.writeAttr(indent, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *colorWriter) writeAttr(indent string, a attrs.Attr) error {
+	switch a.Value.Kind() {
+	case attrs.KindFloat64:
+		_, err := fmt.Fprintf(w.writer, "%s%s=%f\n", indent, a.Key, a.Value.Float64())
+		return err
+	case attrs.KindError:
+		if a.Value.Error() == nil {
+			_, err := fmt.Fprintf(w.writer, "%s%s=%v\n", indent, a.Key, nil)
+			return err
+		}
+		detail := attrs.Details(a.Value.Error())
+		if _, err := fmt.Fprintf(w.writer, "%s%s=%s\n", indent, a.Key, indentLines(detail.Message, indent+"  ")); err != nil {
+			return err
+		}
+		return w.writeErrorDetail(indent+"  ", detail)
+	case attrs.KindGroup:
+		if a.Key != "" {
+			if _, err := fmt.Fprintf(w.writer, "%s%s:\n", indent, a.Key); err != nil {
 				return err
 			}
-		case attrs.AttrValue[any]:
-			if _, err := fmt.Fprintf(w.writer, "  %s=%v\n", a.Key(), a.Value()); err != nil {
+			indent += "  "
+		}
+		for _, member := range a.Value.Group() {
+			if err := w.writeAttr(indent, member); err != nil {
 				return err
 			}
 		}
+		return nil
+	default:
+		_, err := fmt.Fprintf(w.writer, "%s%s=%s\n", indent, a.Key, a.Value.String())
+		return err
+	}
+}
+
+// writeErrorDetail writes the location and stack of an error, followed by
+// each of its causes, indented further at each level of the chain.
+func (w *colorWriter) writeErrorDetail(indent string, detail attrs.ErrorDetail) error {
+	if detail.Location != "" {
+		LocationColor.Fprintf(w.writer, "%sat %s:%d\n", indent, detail.Location, detail.Line)
+	}
+	for _, frame := range detail.Stack {
+		if _, err := fmt.Fprintf(w.writer, "%s%s\n", indent, frame); err != nil {
+			return err
+		}
+	}
+	for _, cause := range detail.Causes {
+		if _, err := fmt.Fprintf(w.writer, "%scaused by: %s\n", indent, indentLines(cause.Message, indent+"  ")); err != nil {
+			return err
+		}
+		if err := w.writeErrorDetail(indent+"  ", cause); err != nil {
+			return err
+		}
 	}
 	return nil
 }
+
+// indentLines indents all but the first line of a multi-line message, such as
+// that of a joined error.
+func indentLines(message, indent string) string {
+	return strings.ReplaceAll(message, "\n", "\n"+indent)
+}
@@ -3,7 +3,10 @@
 
 package loggo
 
-import "context"
+import (
+	"context"
+	"sync/atomic"
+)
 
 // Do not change rootName: modules.resolve() will misbehave if it isn't "".
 const (
@@ -11,15 +14,27 @@ const (
 )
 
 type module struct {
-	name    string
-	level   Level
-	parent  *module
-	context *Context
+	name  string
+	level Level
+	// patternLevel is the level of the longest config pattern matching the
+	// module, which applies if the level of the module is unspecified.
+	patternLevel Level
+	// effectiveLevel caches the level of the module, or of its nearest
+	// ancestor with a specified level, so that checking whether a level is
+	// enabled doesn't walk the parent chain. It is updated, with the
+	// context's modulesMutex held, whenever a level in its ancestry changes.
+	effectiveLevel Level
+	parent         *module
+	children       []*module
+	context        *Context
 
 	tags       []string
 	tagsLookup map[string]struct{}
 
 	labels Labels
+	// attrs holds the attrs added to every entry logged by the module. It
+	// is replaced by ChildWithAttrs while other goroutines may be logging.
+	attrs atomic.Pointer[[]any]
 }
 
 // Name returns the module's name.
@@ -30,22 +45,45 @@ func (m *module) Name() string {
 	return m.name
 }
 
+// getAttrs returns the attrs added to every entry logged by the module.
+func (m *module) getAttrs() []any {
+	if values := m.attrs.Load(); values != nil {
+		return *values
+	}
+	return nil
+}
+
+// setAttrs replaces the attrs added to every entry logged by the module.
+func (m *module) setAttrs(values []any) {
+	m.attrs.Store(&values)
+}
+
 func (m *module) willWrite(level Level) bool {
 	if level < TRACE || level > CRITICAL {
 		return false
 	}
-	return level >= m.getEffectiveLogLevel()
+	return level >= m.effectiveLevel.get()
 }
 
 func (m *module) getEffectiveLogLevel() Level {
-	// Note: the root module is guaranteed to have a
-	// specified logging level, so acts as a suitable sentinel
-	// for this loop.
-	for {
-		if level := m.level.get(); level != UNSPECIFIED {
-			return level
-		}
-		m = m.parent
+	return m.effectiveLevel.get()
+}
+
+// updateEffectiveLevels recomputes the cached effective level of the module
+// and its descendants. It must be called with the context's modulesMutex held.
+func (m *module) updateEffectiveLevels() {
+	// Note: the root module is guaranteed to have a specified logging
+	// level, so it never reads the level of its parent, which is itself.
+	level := m.level.get()
+	if level == UNSPECIFIED {
+		level = m.patternLevel.get()
+	}
+	if level == UNSPECIFIED {
+		level = m.parent.effectiveLevel.get()
+	}
+	m.effectiveLevel.set(level)
+	for _, child := range m.children {
+		child.updateEffectiveLevels()
 	}
 }
 
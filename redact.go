@@ -0,0 +1,125 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package loggo
+
+import (
+	"maps"
+	"regexp"
+	"strings"
+
+	"github.com/juju/loggo/v3/attrs"
+)
+
+// DefaultRedactedKeys are the key names redacted by a Redactor when none are
+// specified.
+var DefaultRedactedKeys = []string{"password", "token", "secret"}
+
+// RedactorConfig holds the configuration for a Redactor.
+type RedactorConfig struct {
+	// Keys are the names that mark a label or attr as sensitive. A key
+	// matches if it contains any of the names, ignoring case, so that
+	// "password" also matches "db-password". If Keys is empty,
+	// DefaultRedactedKeys are used.
+	Keys []string
+	// Patterns are applied to the message of each entry, and any text they
+	// match is replaced with the mask.
+	Patterns []*regexp.Regexp
+	// Mask is written in place of redacted values. If it is empty,
+	// attrs.Redacted is used.
+	Mask string
+}
+
+// Redactor masks sensitive data in entries before they are written. A
+// Redactor is set on a Context with SetRedactor.
+type Redactor struct {
+	keys     []string
+	patterns []*regexp.Regexp
+	mask     string
+}
+
+// NewRedactor returns a Redactor with the given configuration.
+func NewRedactor(config RedactorConfig) *Redactor {
+	keys := config.Keys
+	if len(keys) == 0 {
+		keys = DefaultRedactedKeys
+	}
+	r := &Redactor{
+		keys:     make([]string, len(keys)),
+		patterns: append([]*regexp.Regexp(nil), config.Patterns...),
+		mask:     config.Mask,
+	}
+	for i, key := range keys {
+		r.keys[i] = strings.ToLower(key)
+	}
+	if r.mask == "" {
+		r.mask = attrs.Redacted
+	}
+	return r
+}
+
+// Redact masks the message, labels and attrs of the entry. The labels and
+// attrs are copied before they are changed, as they may be shared with the
+// logger that created the entry. Attr values are never resolved to decide
+// whether they should be redacted, only their keys are considered.
+func (r *Redactor) Redact(entry *Entry) {
+	for _, pattern := range r.patterns {
+		entry.Message = pattern.ReplaceAllLiteralString(entry.Message, r.mask)
+	}
+
+	copied := false
+	for key := range entry.Labels {
+		if !r.matches(key) {
+			continue
+		}
+		if !copied {
+			entry.Labels = maps.Clone(entry.Labels)
+			copied = true
+		}
+		entry.Labels[key] = r.mask
+	}
+
+	if redacted, ok := r.redactAttrs(entry.Attrs); ok {
+		entry.Attrs = redacted
+	}
+}
+
+// redactAttrs returns a copy of the attrs with the sensitive ones masked,
+// reporting whether any were. The attrs of groups are redacted too.
+func (r *Redactor) redactAttrs(entryAttrs []any) ([]any, bool) {
+	var result []any
+	for i, attr := range entryAttrs {
+		var replacement any
+		switch a := attr.(type) {
+		case attrs.AttrValue[[]any]:
+			if r.matches(a.Key()) {
+				replacement = attrs.String(a.Key(), r.mask)
+			} else if members, ok := r.redactAttrs(a.Value()); ok {
+				replacement = attrs.Group(a.Key(), members...)
+			}
+		case interface{ Key() string }:
+			if r.matches(a.Key()) {
+				replacement = attrs.String(a.Key(), r.mask)
+			}
+		}
+		if replacement == nil {
+			continue
+		}
+		if result == nil {
+			result = append([]any(nil), entryAttrs...)
+		}
+		result[i] = replacement
+	}
+	return result, result != nil
+}
+
+// matches reports whether the key names sensitive data.
+func (r *Redactor) matches(key string) bool {
+	key = strings.ToLower(key)
+	for _, name := range r.keys {
+		if strings.Contains(key, name) {
+			return true
+		}
+	}
+	return false
+}
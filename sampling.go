@@ -0,0 +1,298 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package loggo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/juju/loggo/v3/attrs"
+)
+
+// Clock provides the current time, and timers, to writers that depend on
+// them, so that they can be controlled in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// AfterFunc waits for the duration to elapse and then calls f in its
+	// own goroutine, as time.AfterFunc does.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a timer created by a Clock.
+type Timer interface {
+	// Stop prevents the timer from firing, returning false if it has
+	// already fired or been stopped.
+	Stop() bool
+}
+
+type wallClock struct{}
+
+// Now implements Clock.
+func (wallClock) Now() time.Time {
+	return time.Now()
+}
+
+// AfterFunc implements Clock.
+func (wallClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+// DefaultSamplingInterval is the interval used by a sampling writer when none
+// is specified.
+const DefaultSamplingInterval = time.Second
+
+// SuppressedKey is the key of the attribute that holds the number of
+// suppressed entries in a summary entry.
+const SuppressedKey = "suppressed"
+
+// SamplingConfig holds the configuration for a sampling writer.
+type SamplingConfig struct {
+	// First is the number of entries from each call site that are written
+	// in each interval before sampling starts.
+	First int
+	// Thereafter is the sampling rate once First entries have been
+	// written: every Thereafter-th entry is written. If it is not
+	// positive, no more entries are written until the next interval.
+	Thereafter int
+	// Interval is the length of each sampling period. If it is not
+	// positive, DefaultSamplingInterval is used.
+	Interval time.Duration
+	// Clock is used to get the current time and to time the sweeps of the
+	// call sites. If it is nil, the wall clock is used.
+	Clock Clock
+	// ErrorHandler, if set, is called with any error returned by the
+	// wrapped writer when summaries are written by the sweeper timer.
+	// Errors are otherwise discarded, as there is no logging call to
+	// return them to.
+	ErrorHandler func(error)
+}
+
+// NewSamplingWriter returns a Writer that limits the number of entries that
+// are passed on to the provided writer from each call site, identified by
+// Entry.PC. In each interval, the first entries from a call site are written,
+// followed by every Mth entry, as set in the config.
+//
+// When entries from a call site have been suppressed, a summary entry
+// reporting how many were suppressed is written once the interval for that
+// call site has ended. The summary has the module and location of the call
+// site, and the number of suppressed entries as the SuppressedKey attribute.
+// The call sites are swept for ended intervals by a timer that runs each
+// interval while there are call sites, so summaries are written even when
+// logging stops. The calls to the provided writer are serialised, as the
+// timer writes summaries from its own goroutine.
+func NewSamplingWriter(writer Writer, config SamplingConfig) Writer {
+	if config.Interval <= 0 {
+		config.Interval = DefaultSamplingInterval
+	}
+	if config.Clock == nil {
+		config.Clock = wallClock{}
+	}
+	return &samplingWriter{
+		writer: NewSerialWriter(writer),
+		config: config,
+		sites:  make(map[callSite]*sampledSite),
+	}
+}
+
+type samplingWriter struct {
+	// writer serialises the calls to the provided writer.
+	writer Writer
+	config SamplingConfig
+
+	mu    sync.Mutex
+	sites map[callSite]*sampledSite
+	// sweeper is the timer that sweeps the call sites, which runs while
+	// there are call sites.
+	sweeper Timer
+}
+
+// callSite identifies where an entry was logged. The filename and line are
+// used in addition to the PC, for entries that were created without one.
+type callSite struct {
+	pc       uintptr
+	filename string
+	line     int
+}
+
+type sampledSite struct {
+	start      time.Time
+	count      int
+	suppressed int
+	// summary holds the details of the suppressed entries used to build
+	// the summary entry.
+	summary Entry
+}
+
+// Concurrent implements ConcurrentWriter.
+func (w *samplingWriter) Concurrent() {}
+
+// Write implements Writer.
+func (w *samplingWriter) Write(ctx context.Context, entry Entry) error {
+	now := w.config.Clock.Now()
+	site := callSite{pc: entry.PC, filename: entry.Filename, line: entry.Line}
+
+	w.mu.Lock()
+	var summaries []Entry
+	sampled, found := w.sites[site]
+	if found && now.Sub(sampled.start) >= w.config.Interval {
+		if summary, ok := sampled.summarise(now); ok {
+			summaries = append(summaries, summary)
+		}
+		found = false
+	}
+	if !found {
+		sampled = &sampledSite{start: now}
+		w.sites[site] = sampled
+	}
+	if w.sweeper == nil {
+		w.sweeper = w.config.Clock.AfterFunc(w.config.Interval, w.sweepSites)
+	}
+	sampled.count++
+	write := w.sample(sampled.count)
+	if !write {
+		sampled.suppress(entry)
+	}
+	w.mu.Unlock()
+
+	var errs []error
+	for _, summary := range summaries {
+		if err := w.writer.Write(ctx, summary); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if write {
+		if err := w.writer.Write(ctx, entry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Flush implements Flusher. The summaries for all the call sites with
+// suppressed entries are written, and the wrapped writer is flushed.
+func (w *samplingWriter) Flush(ctx context.Context) error {
+	if err := w.writeSummaries(ctx); err != nil {
+		return err
+	}
+	return FlushWriter(ctx, w.writer)
+}
+
+// Close implements Closer. The summaries for all the call sites with
+// suppressed entries are written, and the wrapped writer is closed.
+func (w *samplingWriter) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if w.sweeper != nil {
+		w.sweeper.Stop()
+		w.sweeper = nil
+	}
+	w.mu.Unlock()
+	if err := w.writeSummaries(ctx); err != nil {
+		return err
+	}
+	return CloseWriter(ctx, w.writer)
+}
+
+// writeSummaries writes the summaries for all the call sites with
+// suppressed entries, and forgets the call sites.
+func (w *samplingWriter) writeSummaries(ctx context.Context) error {
+	now := w.config.Clock.Now()
+
+	w.mu.Lock()
+	var summaries []Entry
+	for _, sampled := range w.sites {
+		if summary, ok := sampled.summarise(now); ok {
+			summaries = append(summaries, summary)
+		}
+	}
+	w.sites = make(map[callSite]*sampledSite)
+	w.mu.Unlock()
+
+	var errs []error
+	for _, summary := range summaries {
+		if err := w.writer.Write(ctx, summary); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sample reports whether the count-th entry from a call site in the current
+// interval should be written.
+func (w *samplingWriter) sample(count int) bool {
+	if count <= w.config.First {
+		return true
+	}
+	if w.config.Thereafter <= 0 {
+		return false
+	}
+	return (count-w.config.First)%w.config.Thereafter == 0
+}
+
+// sweepSites is called by the sweeper timer to write the summaries for the
+// call sites whose interval has ended. The timer is started again while
+// there are call sites left.
+func (w *samplingWriter) sweepSites() {
+	now := w.config.Clock.Now()
+
+	w.mu.Lock()
+	summaries := w.sweep(now)
+	w.sweeper = nil
+	if len(w.sites) > 0 {
+		w.sweeper = w.config.Clock.AfterFunc(w.config.Interval, w.sweepSites)
+	}
+	w.mu.Unlock()
+
+	for _, summary := range summaries {
+		if err := w.writer.Write(context.Background(), summary); err != nil && w.config.ErrorHandler != nil {
+			w.config.ErrorHandler(err)
+		}
+	}
+}
+
+// sweep removes the call sites whose interval has ended, returning the
+// summaries of any suppressed entries, so that call sites that have stopped
+// logging still get reported and don't hold on to memory.
+func (w *samplingWriter) sweep(now time.Time) []Entry {
+	var summaries []Entry
+	for key, sampled := range w.sites {
+		if now.Sub(sampled.start) < w.config.Interval {
+			continue
+		}
+		if summary, ok := sampled.summarise(now); ok {
+			summaries = append(summaries, summary)
+		}
+		delete(w.sites, key)
+	}
+	return summaries
+}
+
+func (s *sampledSite) suppress(entry Entry) {
+	if s.suppressed == 0 || entry.Level > s.summary.Level {
+		s.summary.Level = entry.Level
+	}
+	s.summary.Module = entry.Module
+	s.summary.Filename = entry.Filename
+	s.summary.Line = entry.Line
+	s.summary.PC = entry.PC
+	s.suppressed++
+}
+
+// summarise returns the summary entry for the suppressed entries, if there
+// are any.
+func (s *sampledSite) summarise(now time.Time) (Entry, bool) {
+	if s.suppressed == 0 {
+		return Entry{}, false
+	}
+	summary := s.summary
+	summary.Timestamp = now
+	summary.Message = fmt.Sprintf("suppressed %d log entries from %s:%d",
+		s.suppressed, filepath.Base(summary.Filename), summary.Line)
+	summary.Attrs = []any{attrs.Int(SuppressedKey, s.suppressed)}
+	return summary, true
+}
@@ -0,0 +1,330 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package loggo_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/juju/loggo/v3"
+	"github.com/juju/loggo/v3/attrs"
+	"github.com/juju/tc"
+)
+
+type SamplingWriterSuite struct{}
+
+func TestSamplingWriterSuite(t *testing.T) {
+	tc.Run(t, &SamplingWriterSuite{})
+}
+
+// testClock is a Clock that only moves when advanced. Its timers are fired
+// by Advance, in the goroutine that calls it, so that tests can see their
+// effect as soon as Advance returns.
+type testClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*testTimer
+}
+
+type testTimer struct {
+	clock   *testClock
+	when    time.Time
+	f       func()
+	stopped bool
+}
+
+// Stop implements loggo.Timer.
+func (t *testTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	stopped := t.stopped
+	t.stopped = true
+	return !stopped
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) AfterFunc(d time.Duration, f func()) loggo.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timer := &testTimer{clock: c, when: c.now.Add(d), f: f}
+	c.timers = append(c.timers, timer)
+	return timer
+}
+
+// Advance moves the clock on, firing the timers that become due in the order
+// that they are due, with the clock set to when each is due.
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now.Add(d)
+	for {
+		next := -1
+		for i, timer := range c.timers {
+			if !timer.when.After(end) && (next < 0 || timer.when.Before(c.timers[next].when)) {
+				next = i
+			}
+		}
+		if next < 0 {
+			break
+		}
+		timer := c.timers[next]
+		c.timers = append(c.timers[:next], c.timers[next+1:]...)
+		if timer.stopped {
+			continue
+		}
+		timer.stopped = true
+		c.now = timer.when
+		c.mu.Unlock()
+		timer.f()
+		c.mu.Lock()
+	}
+	c.now = end
+	c.mu.Unlock()
+}
+
+// pending returns the number of timers waiting to fire.
+func (c *testClock) pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count := 0
+	for _, timer := range c.timers {
+		if !timer.stopped {
+			count++
+		}
+	}
+	return count
+}
+
+func siteEntry(pc uintptr, level loggo.Level, message string) loggo.Entry {
+	return loggo.Entry{
+		Level:    level,
+		Module:   "test",
+		Filename: "/path/to/file.go",
+		Line:     int(pc),
+		PC:       pc,
+		Message:  message,
+	}
+}
+
+func (*SamplingWriterSuite) TestFirstThenEveryMth(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	clock := newTestClock()
+	sampler := loggo.NewSamplingWriter(writer, loggo.SamplingConfig{
+		First:      2,
+		Thereafter: 3,
+		Interval:   time.Second,
+		Clock:      clock,
+	})
+
+	for i := 1; i <= 10; i++ {
+		err := sampler.Write(c.Context(), siteEntry(1, loggo.INFO, "entry"))
+		c.Assert(err, tc.IsNil)
+	}
+	// Entries 1 and 2, then 5 and 8.
+	c.Check(writer.Log(), tc.HasLen, 4)
+}
+
+func (*SamplingWriterSuite) TestCallSitesSampledSeparately(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	sampler := loggo.NewSamplingWriter(writer, loggo.SamplingConfig{
+		First: 1,
+		Clock: newTestClock(),
+	})
+
+	for i := 0; i < 3; i++ {
+		_ = sampler.Write(c.Context(), siteEntry(1, loggo.INFO, "first site"))
+		_ = sampler.Write(c.Context(), siteEntry(2, loggo.INFO, "second site"))
+	}
+	c.Check(messages(writer.Log()), tc.DeepEquals, []string{"first site", "second site"})
+}
+
+func (*SamplingWriterSuite) TestSummaryAfterInterval(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	clock := newTestClock()
+	sampler := loggo.NewSamplingWriter(writer, loggo.SamplingConfig{
+		First:    1,
+		Interval: time.Second,
+		Clock:    clock,
+	})
+
+	_ = sampler.Write(c.Context(), siteEntry(1, loggo.INFO, "entry"))
+	_ = sampler.Write(c.Context(), siteEntry(1, loggo.WARNING, "entry"))
+	_ = sampler.Write(c.Context(), siteEntry(1, loggo.INFO, "entry"))
+	c.Assert(writer.Log(), tc.HasLen, 1)
+
+	clock.Advance(time.Second)
+	_ = sampler.Write(c.Context(), siteEntry(1, loggo.INFO, "entry"))
+
+	logs := writer.Log()
+	c.Assert(logs, tc.HasLen, 3)
+	summary := logs[1]
+	c.Check(summary.Message, tc.Equals, "suppressed 2 log entries from file.go:1")
+	c.Check(summary.Level, tc.Equals, loggo.WARNING)
+	c.Check(summary.Module, tc.Equals, "test")
+	c.Check(summary.PC, tc.Equals, uintptr(1))
+	c.Check(summary.Timestamp, tc.Equals, clock.Now())
+	c.Assert(summary.Attrs, tc.HasLen, 1)
+	c.Check(summary.Attrs[0].(attrs.AttrValue[int]).Key(), tc.Equals, loggo.SuppressedKey)
+	c.Check(summary.Attrs[0].(attrs.AttrValue[int]).Value(), tc.Equals, 2)
+	c.Check(logs[2].Message, tc.Equals, "entry")
+}
+
+func (*SamplingWriterSuite) TestSummaryForQuietCallSite(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	clock := newTestClock()
+	sampler := loggo.NewSamplingWriter(writer, loggo.SamplingConfig{
+		First:    1,
+		Interval: time.Second,
+		Clock:    clock,
+	})
+
+	for i := 0; i < 5; i++ {
+		_ = sampler.Write(c.Context(), siteEntry(1, loggo.INFO, "noisy"))
+	}
+	clock.Advance(500 * time.Millisecond)
+	c.Check(messages(writer.Log()), tc.DeepEquals, []string{"noisy"})
+
+	// The summary is written when the interval ends, without any more
+	// logging.
+	clock.Advance(500 * time.Millisecond)
+	logs := writer.Log()
+	c.Check(messages(logs), tc.DeepEquals, []string{
+		"noisy",
+		"suppressed 4 log entries from file.go:1",
+	})
+	c.Check(logs[1].Timestamp, tc.Equals, clock.Now())
+
+	// With no call sites left, the sweeper stops.
+	c.Check(clock.pending(), tc.Equals, 0)
+}
+
+func (*SamplingWriterSuite) TestNoSummaryWithoutSuppression(c *tc.C) {
+	writer := &loggo.TestWriter{}
+	clock := newTestClock()
+	sampler := loggo.NewSamplingWriter(writer, loggo.SamplingConfig{
+		First:    5,
+		Interval: time.Second,
+		Clock:    clock,
+	})
+
+	_ = sampler.Write(c.Context(), siteEntry(1, loggo.INFO, "entry"))
+	clock.Advance(time.Second)
+	_ = sampler.Write(c.Context(), siteEntry(1, loggo.INFO, "entry"))
+	c.Check(messages(writer.Log()), tc.DeepEquals, []string{"entry", "entry"})
+}
+
+func (*SamplingWriterSuite) TestConcurrentLogging(c *tc.C) {
+	counter := &countingWriter{}
+	sampler := loggo.NewSamplingWriter(counter, loggo.SamplingConfig{
+		First:      10,
+		Thereafter: 10,
+		Interval:   time.Hour,
+	})
+	logContext := loggo.NewContext(loggo.TRACE)
+	c.Assert(logContext.AddWriter("sampler", sampler), tc.IsNil)
+	logger := logContext.GetLogger("test")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = logger.Infof(context.Background(), "hot loop")
+			}
+		}()
+	}
+	wg.Wait()
+	// The first 10 entries, then every 10th of the remaining 990.
+	c.Check(counter.count.Load(), tc.Equals, int64(109))
+}
+
+func (*SamplingWriterSuite) TestSweepWhileLogging(c *tc.C) {
+	// The sweeper writes summaries while entries are being written, which
+	// must not call the wrapped writer at the same time.
+	wrapped := &unsafeWriter{}
+	sampler := loggo.NewSamplingWriter(wrapped, loggo.SamplingConfig{
+		First:    1,
+		Interval: time.Millisecond,
+	})
+
+	for i := 0; i < 500; i++ {
+		_ = sampler.Write(c.Context(), siteEntry(1, loggo.INFO, "hot loop"))
+		if i%50 == 0 {
+			time.Sleep(2 * time.Millisecond)
+		}
+	}
+	c.Assert(loggo.CloseWriter(c.Context(), sampler), tc.IsNil)
+
+	// Every entry is either written or counted as suppressed.
+	total := 0
+	for _, e := range wrapped.entries {
+		if len(e.Attrs) == 0 {
+			total++
+			continue
+		}
+		total += e.Attrs[0].(attrs.AttrValue[int]).Value()
+	}
+	c.Check(total, tc.Equals, 500)
+}
+
+func (*SamplingWriterSuite) TestSweepErrorHandler(c *tc.C) {
+	clock := newTestClock()
+	var handled []error
+	failing := writerFunc(func(_ context.Context, entry loggo.Entry) error {
+		if entry.Message == "noisy" {
+			return nil
+		}
+		return errors.New("boom")
+	})
+	sampler := loggo.NewSamplingWriter(failing, loggo.SamplingConfig{
+		First:    1,
+		Interval: time.Second,
+		Clock:    clock,
+		ErrorHandler: func(err error) {
+			handled = append(handled, err)
+		},
+	})
+
+	for i := 0; i < 3; i++ {
+		_ = sampler.Write(c.Context(), siteEntry(1, loggo.INFO, "noisy"))
+	}
+	clock.Advance(time.Second)
+
+	c.Assert(handled, tc.HasLen, 1)
+	c.Check(handled[0], tc.ErrorMatches, "boom")
+}
+
+func (*SamplingWriterSuite) TestFlushWritesSummaries(c *tc.C) {
+	var calls []string
+	wrapped := &lifecycleWriter{writer: writer{name: "wrapped"}, calls: &calls}
+	sampler := loggo.NewSamplingWriter(wrapped, loggo.SamplingConfig{
+		First:    1,
+		Interval: time.Hour,
+		Clock:    newTestClock(),
+	})
+
+	for i := 0; i < 3; i++ {
+		_ = sampler.Write(c.Context(), siteEntry(1, loggo.INFO, "entry"))
+	}
+	err := loggo.FlushWriter(c.Context(), sampler)
+	c.Assert(err, tc.IsNil)
+
+	c.Check(messages(wrapped.Log()), tc.DeepEquals, []string{
+		"entry",
+		"suppressed 2 log entries from file.go:1",
+	})
+	c.Check(calls, tc.DeepEquals, []string{"flush wrapped"})
+}
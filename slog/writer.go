@@ -2,8 +2,8 @@ package slog
 
 import (
 	"context"
+	"encoding/base64"
 	"log/slog"
-	"time"
 
 	"github.com/juju/loggo/v3"
 	"github.com/juju/loggo/v3/attrs"
@@ -35,32 +35,67 @@ func (w *slogWriter) Write(ctx context.Context, entry loggo.Entry) error {
 	for key, value := range entry.Labels {
 		record.AddAttrs(slog.Any(key, value))
 	}
-	for _, attr := range entry.Attrs {
-		switch a := attr.(type) {
-		case attrs.AttrValue[string]:
-			record.AddAttrs(slog.String(a.Key(), a.Value()))
-		case attrs.AttrValue[int]:
-			record.AddAttrs(slog.Int(a.Key(), a.Value()))
-		case attrs.AttrValue[int64]:
-			record.AddAttrs(slog.Int64(a.Key(), a.Value()))
-		case attrs.AttrValue[uint64]:
-			record.AddAttrs(slog.Uint64(a.Key(), a.Value()))
-		case attrs.AttrValue[float64]:
-			record.AddAttrs(slog.Float64(a.Key(), a.Value()))
-		case attrs.AttrValue[bool]:
-			record.AddAttrs(slog.Bool(a.Key(), a.Value()))
-		case attrs.AttrValue[time.Time]:
-			record.AddAttrs(slog.Time(a.Key(), a.Value()))
-		case attrs.AttrValue[time.Duration]:
-			record.AddAttrs(slog.Duration(a.Key(), a.Value()))
-		case attrs.AttrValue[any]:
-			record.AddAttrs(slog.Any(a.Key(), a.Value()))
-		}
+	for _, a := range entry.NormalizedAttrs() {
+		record.AddAttrs(slogAttr(a))
+	}
+	if len(entry.Stack) > 0 {
+		record.AddAttrs(slog.Any("stack", entry.Stack))
 	}
 
 	return w.writer.Handle(ctx, record)
 }
 
+// Concurrent implements loggo.ConcurrentWriter, as slog handlers must be
+// safe for concurrent use.
+func (w *slogWriter) Concurrent() {}
+
+// slogAttr converts a normalised attr to a slog.Attr.
+func slogAttr(a attrs.Attr) slog.Attr {
+	return slog.Attr{Key: a.Key, Value: slogValue(a.Value)}
+}
+
+// slogValue converts a value to a slog.Value. Groups are converted to slog
+// groups, errors to their details and bytes to base64.
+func slogValue(v attrs.Value) slog.Value {
+	switch v.Kind() {
+	case attrs.KindString:
+		return slog.StringValue(v.String())
+	case attrs.KindInt64:
+		return slog.Int64Value(v.Int64())
+	case attrs.KindUint64:
+		return slog.Uint64Value(v.Uint64())
+	case attrs.KindFloat64:
+		return slog.Float64Value(v.Float64())
+	case attrs.KindBool:
+		return slog.BoolValue(v.Bool())
+	case attrs.KindTime:
+		return slog.TimeValue(v.Time())
+	case attrs.KindDuration:
+		return slog.DurationValue(v.Duration())
+	case attrs.KindBytes:
+		return slog.StringValue(base64.StdEncoding.EncodeToString(v.Bytes()))
+	case attrs.KindStringer:
+		if v.Stringer() == nil {
+			return slog.AnyValue(nil)
+		}
+		return slog.StringValue(v.String())
+	case attrs.KindError:
+		if v.Error() == nil {
+			return slog.AnyValue(nil)
+		}
+		return slog.AnyValue(attrs.Details(v.Error()))
+	case attrs.KindGroup:
+		group := v.Group()
+		members := make([]slog.Attr, len(group))
+		for i, member := range group {
+			members[i] = slog.Attr{Key: member.Key, Value: slogValue(member.Value)}
+		}
+		return slog.GroupValue(members...)
+	default:
+		return slog.AnyValue(v.Any())
+	}
+}
+
 // Level function allows levels to be mapped to slog levels. Although,
 // slog doesn't explicitly implement all the levels that we require for mapping
 // it does allow for custom levels to be added. This is done by using the
@@ -4,8 +4,14 @@
 package slog
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,12 +21,14 @@ import (
 
 // mockHandler captures records written via Handle for inspection.
 type mockHandler struct {
-	records []slog.Record
+	records  []slog.Record
+	contexts []context.Context
 }
 
 func (h *mockHandler) Enabled(_ context.Context, _ slog.Level) bool { return true }
-func (h *mockHandler) Handle(_ context.Context, r slog.Record) error {
+func (h *mockHandler) Handle(ctx context.Context, r slog.Record) error {
 	h.records = append(h.records, r)
+	h.contexts = append(h.contexts, ctx)
 	return nil
 }
 func (h *mockHandler) WithAttrs(_ []slog.Attr) slog.Handler { return h }
@@ -170,6 +178,144 @@ func TestWriteWithAllAttrTypes(t *testing.T) {
 	}
 }
 
+type ctxKey struct{}
+
+func TestWritePassesContextToHandler(t *testing.T) {
+	handler := &mockHandler{}
+	logContext := loggo.NewContext(loggo.INFO)
+	if err := logContext.AddWriter("slog", NewSlogWriter(handler)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	_ = logContext.GetLogger("test").Infof(ctx, "hello")
+
+	if len(handler.contexts) != 1 {
+		t.Fatalf("expected 1 context, got %d", len(handler.contexts))
+	}
+	if v := handler.contexts[0].Value(ctxKey{}); v != "value" {
+		t.Errorf("expected context value %q, got %v", "value", v)
+	}
+}
+
+func TestWriteSecretAttr(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewSlogWriter(slog.NewJSONHandler(&buf, nil))
+
+	entry := loggo.Entry{
+		Level:   loggo.INFO,
+		Message: "connecting",
+		Attrs:   []any{attrs.Secret("password", "hunter2")},
+	}
+	if err := w.Write(context.Background(), entry); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"password":"***"`) || strings.Contains(buf.String(), "hunter2") {
+		t.Errorf("expected redacted password, got %s", buf.String())
+	}
+}
+
+func TestWriteGroupAttr(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewSlogWriter(slog.NewJSONHandler(&buf, nil))
+
+	entry := loggo.Entry{
+		Level:   loggo.INFO,
+		Message: "request",
+		Attrs: []any{
+			attrs.Group("http", attrs.String("method", "GET"), attrs.Int("status", 200)),
+		},
+	}
+	if err := w.Write(context.Background(), entry); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"http":{"method":"GET","status":200}`) {
+		t.Errorf("expected nested group, got %s", buf.String())
+	}
+}
+
+func TestWriteErrorAttr(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewSlogWriter(slog.NewJSONHandler(&buf, nil))
+
+	entry := loggo.Entry{
+		Level:   loggo.ERROR,
+		Message: "failed",
+		Attrs: []any{
+			attrs.Err(fmt.Errorf("outer: %w", errors.Join(errors.New("one"), errors.New("two")))),
+		},
+	}
+	if err := w.Write(context.Background(), entry); err != nil {
+		t.Fatal(err)
+	}
+	var record struct {
+		Error attrs.ErrorDetail `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Error.Message != "outer: one\ntwo" {
+		t.Errorf("unexpected message %q", record.Error.Message)
+	}
+	if len(record.Error.Causes) != 1 || len(record.Error.Causes[0].Causes) != 2 {
+		t.Errorf("expected nested causes, got %s", buf.String())
+	}
+}
+
+func TestWriteStack(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewSlogWriter(slog.NewJSONHandler(&buf, nil))
+
+	stack := []loggo.Frame{
+		{Function: "main.run", File: "/src/main.go", Line: 42},
+		{Function: "main.main", File: "/src/main.go", Line: 10},
+	}
+	entry := loggo.Entry{Level: loggo.ERROR, Message: "failed", Stack: stack}
+	if err := w.Write(context.Background(), entry); err != nil {
+		t.Fatal(err)
+	}
+	var record struct {
+		Stack []loggo.Frame `json:"stack"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(record.Stack, stack) {
+		t.Errorf("expected stack %v, got %s", stack, buf.String())
+	}
+}
+
+func TestWriteCollectionAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewSlogWriter(slog.NewJSONHandler(&buf, nil))
+
+	entry := loggo.Entry{
+		Level:   loggo.INFO,
+		Message: "collections",
+		Attrs: []any{
+			attrs.Strings("names", []string{"a", "b"}),
+			attrs.Ints("counts", []int{1, 2}),
+			attrs.StringMap("tags", map[string]string{"k": "v"}),
+			attrs.Bytes("payload", []byte{0xde, 0xad, 0xbe, 0xef}),
+			attrs.Stringer("timeout", 5*time.Second),
+		},
+	}
+	if err := w.Write(context.Background(), entry); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`"names":["a","b"]`,
+		`"counts":[1,2]`,
+		`"tags":{"k":"v"}`,
+		`"payload":"3q2+7w=="`,
+		`"timeout":"5s"`,
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("expected %s in %s", expected, buf.String())
+		}
+	}
+}
+
 func TestLevelMapping(t *testing.T) {
 	tests := []struct {
 		input    loggo.Level
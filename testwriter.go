@@ -25,6 +25,9 @@ func (writer *TestWriter) Write(_ context.Context, entry Entry) error {
 	return nil
 }
 
+// Concurrent implements ConcurrentWriter.
+func (writer *TestWriter) Concurrent() {}
+
 // Clear removes any saved log messages.
 func (writer *TestWriter) Clear() {
 	writer.mu.Lock()
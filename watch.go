@@ -0,0 +1,125 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package loggo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+	"time"
+)
+
+// configPollInterval is how often WatchConfigFile checks the file for changes.
+var configPollInterval = time.Second
+
+// WatchConfigFile reads the logging configuration from the file at the given
+// path and applies it to the context. The file holds either a configuration
+// string, as parsed by ParseConfigString, with entries optionally on separate
+// lines, or JSON, as decoded by Config.UnmarshalJSON.
+//
+// An error is returned if the file can't be read or parsed. Otherwise the file
+// is polled until ctx is done, and the configuration is applied again whenever
+// it changes. Modules and patterns removed from the file are reset to
+// UNSPECIFIED. Updates that can't be read or parsed are logged to the "loggo"
+// module of the context and ignored. If onChange is not nil, it is called with
+// the old and new configurations read from the file after each change has been
+// applied.
+//
+// The file is polled rather than watched with inotify, so that it works on all
+// platforms. The file should be replaced by renaming a new file over it, as a
+// file being written in place may be read when it is only partially written.
+func WatchConfigFile(ctx context.Context, logContext *Context, path string, onChange func(oldConfig, newConfig Config)) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading logging config: %w", err)
+	}
+	config, err := parseConfigFile(data)
+	if err != nil {
+		return fmt.Errorf("parsing logging config %q: %w", path, err)
+	}
+	logContext.ApplyConfig(config)
+
+	ticker := time.NewTicker(configPollInterval)
+	go func() {
+		defer ticker.Stop()
+		logger := logContext.GetLogger("loggo")
+		readFailed := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			newData, err := os.ReadFile(path)
+			if err != nil {
+				// Only log the first failure, as the file may be missing
+				// for some time.
+				if !readFailed {
+					_ = logger.Errorf(ctx, "reading logging config: %v", err)
+				}
+				readFailed = true
+				continue
+			}
+			readFailed = false
+			if bytes.Equal(newData, data) {
+				continue
+			}
+			data = newData
+
+			newConfig, err := parseConfigFile(data)
+			if err != nil {
+				_ = logger.Errorf(ctx, "ignoring logging config %q: %v", path, err)
+				continue
+			}
+			if maps.Equal(config, newConfig) {
+				continue
+			}
+			logContext.ApplyConfig(configUpdate(config, newConfig))
+			if onChange != nil {
+				onChange(config, newConfig)
+			}
+			config = newConfig
+		}
+	}()
+	return nil
+}
+
+// parseConfigFile parses the contents of a config file, which is either JSON
+// or a configuration string with entries optionally on separate lines.
+func parseConfigFile(data []byte) (Config, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '"') {
+		var config Config
+		if err := json.Unmarshal(trimmed, &config); err != nil {
+			return nil, err
+		}
+		return config, nil
+	}
+
+	var entries []string
+	for _, line := range strings.Split(string(trimmed), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			entries = append(entries, line)
+		}
+	}
+	return ParseConfigString(strings.Join(entries, ";"))
+}
+
+// configUpdate returns the config to apply to change the levels from the old
+// config to the new one, which resets the entries that were removed.
+func configUpdate(oldConfig, newConfig Config) Config {
+	update := make(Config, len(newConfig))
+	maps.Copy(update, newConfig)
+	for name := range oldConfig {
+		if _, ok := newConfig[name]; !ok {
+			update[name] = UNSPECIFIED
+		}
+	}
+	return update
+}
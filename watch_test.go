@@ -0,0 +1,139 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package loggo_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/juju/loggo/v3"
+	"github.com/juju/tc"
+)
+
+type WatchSuite struct {
+	path    string
+	context *loggo.Context
+	writer  *loggo.TestWriter
+	changes chan [2]loggo.Config
+}
+
+func TestWatchSuite(t *testing.T) {
+	tc.Run(t, &WatchSuite{})
+}
+
+func (s *WatchSuite) SetUpTest(c *tc.C) {
+	restore := loggo.SetConfigPollInterval(5 * time.Millisecond)
+	c.Cleanup(restore)
+
+	s.path = filepath.Join(c.MkDir(), "logging.conf")
+	s.context = loggo.NewContext(loggo.WARNING)
+	s.writer = &loggo.TestWriter{}
+	c.Assert(s.context.AddWriter("test", s.writer), tc.IsNil)
+	s.changes = make(chan [2]loggo.Config, 10)
+}
+
+func (s *WatchSuite) onChange(oldConfig, newConfig loggo.Config) {
+	s.changes <- [2]loggo.Config{oldConfig, newConfig}
+}
+
+// writeConfig replaces the config file, so that the watcher never reads a
+// partially written file.
+func (s *WatchSuite) writeConfig(c *tc.C, config string) {
+	tmp := s.path + ".tmp"
+	err := os.WriteFile(tmp, []byte(config), 0o644)
+	c.Assert(err, tc.IsNil)
+	err = os.Rename(tmp, s.path)
+	c.Assert(err, tc.IsNil)
+}
+
+func (s *WatchSuite) nextChange(c *tc.C) (loggo.Config, loggo.Config) {
+	select {
+	case change := <-s.changes:
+		return change[0], change[1]
+	case <-time.After(5 * time.Second):
+		c.Fatalf("timed out waiting for config change")
+		return nil, nil
+	}
+}
+
+func (s *WatchSuite) TestWatchConfigFile(c *tc.C) {
+	s.writeConfig(c, "<root>=ERROR\nfoo.bar=DEBUG\n")
+	err := loggo.WatchConfigFile(c.Context(), s.context, s.path, s.onChange)
+	c.Assert(err, tc.IsNil)
+	c.Check(s.context.Config(), tc.DeepEquals, loggo.Config{"": loggo.ERROR, "foo.bar": loggo.DEBUG})
+
+	s.writeConfig(c, "<root>=ERROR; foo.*=TRACE")
+	oldConfig, newConfig := s.nextChange(c)
+	c.Check(oldConfig, tc.DeepEquals, loggo.Config{"": loggo.ERROR, "foo.bar": loggo.DEBUG})
+	c.Check(newConfig, tc.DeepEquals, loggo.Config{"": loggo.ERROR, "foo.*": loggo.TRACE})
+	// The removed entry reverts to UNSPECIFIED, so the pattern applies.
+	c.Check(s.context.GetLogger("foo.bar").LogLevel(), tc.Equals, loggo.UNSPECIFIED)
+	c.Check(s.context.GetLogger("foo.bar").EffectiveLogLevel(), tc.Equals, loggo.TRACE)
+
+	s.writeConfig(c, `{"<root>": "info"}`)
+	_, newConfig = s.nextChange(c)
+	c.Check(newConfig, tc.DeepEquals, loggo.Config{"": loggo.INFO})
+	c.Check(s.context.Config(), tc.DeepEquals, loggo.Config{"": loggo.INFO})
+}
+
+func (s *WatchSuite) TestMalformedUpdateIgnored(c *tc.C) {
+	s.writeConfig(c, "<root>=ERROR")
+	err := loggo.WatchConfigFile(c.Context(), s.context, s.path, s.onChange)
+	c.Assert(err, tc.IsNil)
+
+	s.writeConfig(c, "<root>=LOUD")
+	s.writeConfigAfterError(c, "<root>=INFO")
+
+	_, newConfig := s.nextChange(c)
+	c.Check(newConfig, tc.DeepEquals, loggo.Config{"": loggo.INFO})
+	c.Check(s.context.Config(), tc.DeepEquals, loggo.Config{"": loggo.INFO})
+}
+
+// writeConfigAfterError waits for an error to be logged, then writes the
+// config.
+func (s *WatchSuite) writeConfigAfterError(c *tc.C, config string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		log := s.writer.Log()
+		if len(log) > 0 {
+			c.Check(log[0].Level, tc.Equals, loggo.ERROR)
+			c.Check(log[0].Module, tc.Equals, "loggo")
+			c.Check(log[0].Message, tc.Matches, `ignoring logging config ".*": unknown severity level "LOUD"`)
+			break
+		}
+		if time.Now().After(deadline) {
+			c.Fatalf("timed out waiting for error to be logged")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	c.Check(s.context.Config(), tc.DeepEquals, loggo.Config{"": loggo.ERROR})
+	s.writeConfig(c, config)
+}
+
+func (s *WatchSuite) TestStopsWhenContextDone(c *tc.C) {
+	s.writeConfig(c, "<root>=ERROR")
+	ctx, cancel := context.WithCancel(c.Context())
+	err := loggo.WatchConfigFile(ctx, s.context, s.path, s.onChange)
+	c.Assert(err, tc.IsNil)
+	cancel()
+
+	// Give the watcher the chance to see the change, had it not stopped.
+	time.Sleep(50 * time.Millisecond)
+	s.writeConfig(c, "<root>=INFO")
+	time.Sleep(50 * time.Millisecond)
+	c.Check(s.changes, tc.HasLen, 0)
+	c.Check(s.context.Config(), tc.DeepEquals, loggo.Config{"": loggo.ERROR})
+}
+
+func (s *WatchSuite) TestInitialErrors(c *tc.C) {
+	err := loggo.WatchConfigFile(c.Context(), s.context, s.path, nil)
+	c.Check(err, tc.ErrorMatches, `reading logging config: .*no such file or directory`)
+
+	s.writeConfig(c, "foo")
+	err = loggo.WatchConfigFile(c.Context(), s.context, s.path, nil)
+	c.Check(err, tc.ErrorMatches, `parsing logging config ".*": config value expected '=', found "foo"`)
+}
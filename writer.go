@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sync"
 )
 
 // DefaultWriterName is the name of the default writer for
@@ -15,6 +16,10 @@ import (
 const DefaultWriterName = "default"
 
 // Writer is implemented by any recipient of log messages.
+//
+// A Context serialises the calls to each of its writers, unless the writer
+// implements ConcurrentWriter, so writers don't need to be safe for
+// concurrent use.
 type Writer interface {
 	// Write writes a message to the Writer with the given level and module
 	// name. The filename and line hold the file name and line number of the
@@ -24,6 +29,50 @@ type Writer interface {
 	Write(ctx context.Context, entry Entry) error
 }
 
+// ConcurrentWriter is implemented by writers that are safe for concurrent use.
+// A Context calls them from the goroutines that are logging without
+// serialising the calls, so that a slow write doesn't hold up the others.
+type ConcurrentWriter interface {
+	Writer
+	// Concurrent marks the writer as safe for concurrent use.
+	Concurrent()
+}
+
+// Flusher is implemented by writers that buffer entries, or write them in the
+// background, and need to be told to write them out.
+type Flusher interface {
+	// Flush writes out any buffered entries, returning when they have been
+	// written or the context is done.
+	Flush(ctx context.Context) error
+}
+
+// Closer is implemented by writers that hold resources which need releasing
+// when the writer is no longer needed.
+type Closer interface {
+	// Close flushes any buffered entries and releases the writer's
+	// resources. The writer must not be used after it is closed.
+	Close(ctx context.Context) error
+}
+
+// FlushWriter flushes the writer if it implements Flusher, otherwise it does
+// nothing. Writers that wrap other writers can use it to pass on a Flush.
+func FlushWriter(ctx context.Context, writer Writer) error {
+	if flusher, ok := writer.(Flusher); ok {
+		return flusher.Flush(ctx)
+	}
+	return nil
+}
+
+// CloseWriter closes the writer if it implements Closer, otherwise it flushes
+// the writer if it implements Flusher. Writers that wrap other writers can use
+// it to pass on a Close.
+func CloseWriter(ctx context.Context, writer Writer) error {
+	if closer, ok := writer.(Closer); ok {
+		return closer.Close(ctx)
+	}
+	return FlushWriter(ctx, writer)
+}
+
 // NewMinLevelWriter returns a Writer that will only pass on the Write calls
 // to the provided writer if the log level is at or above the specified
 // minimum level.
@@ -47,6 +96,54 @@ func (w minLevelWriter) Write(ctx context.Context, entry Entry) error {
 	return w.writer.Write(ctx, entry)
 }
 
+// Flush implements Flusher.
+func (w minLevelWriter) Flush(ctx context.Context) error {
+	return FlushWriter(ctx, w.writer)
+}
+
+// Close implements Closer.
+func (w minLevelWriter) Close(ctx context.Context) error {
+	return CloseWriter(ctx, w.writer)
+}
+
+// NewSerialWriter returns a Writer that serialises the Write calls to the
+// provided writer, for writers that are not safe for concurrent use. A
+// Context does this for each of its writers that isn't a ConcurrentWriter,
+// so it is only needed to share a writer between contexts, or to use it
+// elsewhere.
+func NewSerialWriter(writer Writer) Writer {
+	return &serialWriter{writer: writer}
+}
+
+type serialWriter struct {
+	mu     sync.Mutex
+	writer Writer
+}
+
+// Write writes the log record.
+func (w *serialWriter) Write(ctx context.Context, entry Entry) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.writer.Write(ctx, entry)
+}
+
+// Concurrent implements ConcurrentWriter.
+func (w *serialWriter) Concurrent() {}
+
+// Flush implements Flusher.
+func (w *serialWriter) Flush(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return FlushWriter(ctx, w.writer)
+}
+
+// Close implements Closer.
+func (w *serialWriter) Close(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return CloseWriter(ctx, w.writer)
+}
+
 type simpleWriter struct {
 	writer    io.Writer
 	formatter func(entry Entry) string
@@ -67,6 +164,21 @@ func (simple *simpleWriter) Write(ctx context.Context, entry Entry) error {
 	return err
 }
 
+// Flush implements Flusher. If the underlying io.Writer buffers its output,
+// such as a bufio.Writer, it is flushed.
+func (simple *simpleWriter) Flush(ctx context.Context) error {
+	if flusher, ok := simple.writer.(interface{ Flush() error }); ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+// Close implements Closer. The underlying io.Writer is flushed but not
+// closed, as it is owned by the caller of NewSimpleWriter.
+func (simple *simpleWriter) Close(ctx context.Context) error {
+	return simple.Flush(ctx)
+}
+
 func defaultWriter() Writer {
 	return NewSimpleWriter(os.Stderr, DefaultFormatter)
 }
@@ -4,8 +4,10 @@
 package loggo
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -59,3 +61,66 @@ func (s *SimpleWriterSuite) TestNewSimpleWriterWithLabels(c *tc.C) {
 
 	c.Check(buf.String(), tc.Equals, "<< a message >>\n")
 }
+
+func (s *SimpleWriterSuite) TestFlushBuffered(c *tc.C) {
+	buf := &bytes.Buffer{}
+	buffered := bufio.NewWriter(buf)
+
+	writer := NewSimpleWriter(buffered, func(entry Entry) string {
+		return entry.Message
+	})
+	_ = writer.Write(context.Background(), Entry{Level: INFO, Message: "a message"})
+	c.Check(buf.String(), tc.Equals, "")
+
+	err := FlushWriter(context.Background(), writer)
+	c.Assert(err, tc.IsNil)
+	c.Check(buf.String(), tc.Equals, "a message\n")
+}
+
+func (s *SimpleWriterSuite) TestMinimumLevelWriterForwardsClose(c *tc.C) {
+	buf := &bytes.Buffer{}
+	buffered := bufio.NewWriter(buf)
+
+	writer := NewMinimumLevelWriter(NewSimpleWriter(buffered, func(entry Entry) string {
+		return entry.Message
+	}), INFO)
+	_ = writer.Write(context.Background(), Entry{Level: INFO, Message: "a message"})
+
+	err := CloseWriter(context.Background(), writer)
+	c.Assert(err, tc.IsNil)
+	c.Check(buf.String(), tc.Equals, "a message\n")
+}
+
+type SerialWriterSuite struct{}
+
+func TestSerialWriterSuite(t *testing.T) {
+	tc.Run(t, &SerialWriterSuite{})
+}
+
+// unsafeWriter records entries without any locking of its own.
+type unsafeWriter struct {
+	count int
+}
+
+func (w *unsafeWriter) Write(context.Context, Entry) error {
+	w.count++
+	return nil
+}
+
+func (s *SerialWriterSuite) TestSerialWriter(c *tc.C) {
+	inner := &unsafeWriter{}
+	writer := NewSerialWriter(inner)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = writer.Write(context.Background(), Entry{Level: INFO})
+			}
+		}()
+	}
+	wg.Wait()
+	c.Check(inner.count, tc.Equals, 1000)
+}